@@ -0,0 +1,224 @@
+package katolomb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BundleOptions holds the configuration of a bundle handler.
+//
+// Nested makes bundles JSON objects nested by splitting keys with Separator
+// instead of flat objects with full keys as attributes. MaxAge is the time
+// clients may cache bundles without revalidating them.
+type BundleOptions struct {
+	Nested    bool
+	Separator string
+	MaxAge    time.Duration
+}
+
+type bundleHandler struct {
+	translators map[string]EnumerableTranslator
+	opts        BundleOptions
+}
+
+// DefaultBundleOptions returns the BundleOptions of flat bundles, with the "."
+// separator for nested ones, that clients must always revalidate.
+func DefaultBundleOptions() BundleOptions {
+	return BundleOptions{Separator: "."}
+}
+
+// NewBundleHandler takes a map of locales to EnumerableTranslator and
+// BundleOptions and returns an http.Handler that serves the translations of
+// each locale as a JSON bundle, so that frontends can share translations with
+// the backend.
+//
+// The bundle of a locale is served on GET and HEAD requests to any path
+// ending with the locale, optionally followed by ".json" (e.g. "/i18n/en" or
+// "/i18n/en.json"). The "prefix" query parameter restricts the bundle to the
+// keys starting with it (e.g. "?prefix=checkout."), and the "format" query
+// parameter, with "flat" or "nested" as value, overrides the options' Nested
+// setting.
+//
+// Bundles hold the translation of each key obtained with no properties
+// available, so interpolation declarations are served as they are. Keys that
+// cannot be translated are left out, and so are keys whose route conflicts
+// with a longer key in nested bundles.
+//
+// Responses carry a weak ETag header, shared by their compressed and
+// uncompressed representations, and requests whose If-None-Match header
+// matches it get a 304 Not Modified response. Responses are compressed with
+// gzip for clients accepting it, and carry a Cache-Control header with the
+// options' MaxAge.
+func NewBundleHandler(translators map[string]EnumerableTranslator, opts BundleOptions) http.Handler {
+	return &bundleHandler{translators: translators, opts: opts}
+}
+
+func (bh *bundleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	locale := strings.TrimSuffix(path.Base(r.URL.Path), ".json")
+	translator, ok := bh.translators[locale]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	nested := bh.opts.Nested
+	switch r.URL.Query().Get("format") {
+	case "flat":
+		nested = false
+	case "nested":
+		nested = true
+	}
+	body, err := bh.bundle(translator, r.URL.Query().Get("prefix"), nested)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Vary", "Accept-Encoding")
+	if bh.opts.MaxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(bh.opts.MaxAge.Seconds())))
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	if !acceptsGzip(r) {
+		w.Write(body)
+		return
+	}
+	h.Set("Content-Encoding", "gzip")
+	gw := gzip.NewWriter(w)
+	gw.Write(body)
+	gw.Close()
+}
+
+func (bh *bundleHandler) bundle(translator EnumerableTranslator, prefix string, nested bool) ([]byte, error) {
+	translations := make(map[string]string)
+	props := NewTranslationProperties(nil)
+	for _, key := range translator.Keys() {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		t, err := translator.Translate(key, props)
+		if err != nil {
+			continue
+		}
+		translations[key] = t
+	}
+	var v interface{} = translations
+	if nested {
+		v = nestTranslations(translations, bh.opts.Separator)
+	}
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding bundle: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// nestTranslations returns the translations in a tree of maps, splitting their
+// keys with the separator. Keys whose route conflicts with a longer key (e.g.
+// "a.b" along with "a.b.c") are left out in favour of the longer one.
+func nestTranslations(translations map[string]string, separator string) map[string]interface{} {
+	keys := make([]string, 0, len(translations))
+	for k := range translations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	tree := make(map[string]interface{})
+	for _, key := range keys {
+		route := []string{key}
+		if separator != "" {
+			route = strings.Split(key, separator)
+		}
+		addNestedTranslation(tree, route, translations[key])
+	}
+	return tree
+}
+
+func addNestedTranslation(tree map[string]interface{}, route []string, translation string) {
+	for i, k := range route {
+		v, ok := tree[k]
+		if i == len(route)-1 {
+			if !ok {
+				tree[k] = translation
+			}
+			return
+		}
+		if !ok {
+			v = make(map[string]interface{})
+			tree[k] = v
+		}
+		next, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		tree = next
+	}
+}
+
+// etagMatches reports whether the If-None-Match header value matches the ETag
+// using the weak comparison function, as required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding header lists gzip
+// with a non-zero quality value.
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(encoding, ";")
+		if strings.TrimSpace(params[0]) != "gzip" {
+			continue
+		}
+		accepted := true
+		for _, param := range params[1:] {
+			name, value := param, ""
+			if i := strings.IndexByte(param, '='); i >= 0 {
+				name, value = param[:i], param[i+1:]
+			}
+			if strings.ToLower(strings.TrimSpace(name)) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			accepted = err == nil && q > 0
+		}
+		if accepted {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package katolomb_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestBundleHandler(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslatorWithSeparator([]byte(`{"checkout": {"title": "Checkout", "total": "Total: %{amount}"}, "hello": "Hello!"}`), ".")
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslatorWithSeparator not to return error, got %v", err)
+	}
+	translators := map[string]katolomb.EnumerableTranslator{"en": yt.(katolomb.EnumerableTranslator)}
+	opts := katolomb.DefaultBundleOptions()
+	opts.MaxAge = time.Hour
+	handler := katolomb.NewBundleHandler(translators, opts)
+	testCases := []struct {
+		path     string
+		status   int
+		expected interface{}
+	}{
+		{"/i18n/en", http.StatusOK, map[string]interface{}{"checkout.title": "Checkout", "checkout.total": "Total: %{amount}", "hello": "Hello!"}},
+		{"/i18n/en.json?prefix=checkout.", http.StatusOK, map[string]interface{}{"checkout.title": "Checkout", "checkout.total": "Total: %{amount}"}},
+		{"/i18n/en.json?format=nested", http.StatusOK, map[string]interface{}{"checkout": map[string]interface{}{"title": "Checkout", "total": "Total: %{amount}"}, "hello": "Hello!"}},
+		{"/i18n/es.json", http.StatusNotFound, nil},
+	}
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("expected bundle handler to respond with status %d for %v, got %d", tc.status, tc.path, rec.Code)
+			continue
+		}
+		if tc.expected == nil {
+			continue
+		}
+		var bundle map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &bundle); err != nil {
+			t.Errorf("expected bundle handler to respond with valid JSON for %v, got %v", tc.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(bundle, tc.expected) {
+			t.Errorf("expected bundle handler to respond with %v for %v, got %v", tc.expected, tc.path, bundle)
+		}
+		if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+			t.Errorf("expected bundle handler to respond with max-age of an hour for %v, got %v", tc.path, cc)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/i18n/en", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected bundle handler to respond with status %d to POST requests, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestBundleHandlerCaching(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"hello": "Hello!"}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	handler := katolomb.NewBundleHandler(map[string]katolomb.EnumerableTranslator{"en": yt.(katolomb.EnumerableTranslator)}, katolomb.DefaultBundleOptions())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/en", nil))
+	etag := rec.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected bundle handler to respond with a weak ETag header, got %q", etag)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("expected bundle handler to respond with no-cache without max age, got %v", cc)
+	}
+
+	for _, inm := range []string{etag, `"other", ` + etag, strings.TrimPrefix(etag, "W/")} {
+		req := httptest.NewRequest(http.MethodGet, "/en", nil)
+		req.Header.Set("If-None-Match", inm)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("expected bundle handler to respond with empty %d when If-None-Match is %v, got %d with %q", http.StatusNotModified, inm, rec.Code, rec.Body.String())
+		}
+	}
+	req := httptest.NewRequest(http.MethodGet, "/en", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected bundle handler to respond with %d when If-None-Match does not match, got %d", http.StatusOK, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/en", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected bundle handler to respond with gzip content encoding when accepted, got %q", ce)
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("expected bundle handler to respond with the same ETag when compressing, got %v instead of %v", rec.Header().Get("ETag"), etag)
+	}
+	gr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("expected bundle handler to respond with a valid gzip body, got %v", err)
+	}
+	body, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("expected bundle handler to respond with a valid gzip body, got %v", err)
+	}
+	if string(body) != "{\"hello\":\"Hello!\"}\n" {
+		t.Errorf("expected bundle handler to respond with the compressed bundle, got %q", body)
+	}
+}
+
+func TestBundleHandlerAcceptEncoding(t *testing.T) {
+	mt := katolomb.NewMemoryTranslator(".")
+	mt.Replace(map[string]string{"hello": "Hello!"})
+	handler := katolomb.NewBundleHandler(map[string]katolomb.EnumerableTranslator{"en": mt}, katolomb.DefaultBundleOptions())
+	testCases := []struct {
+		acceptEncoding string
+		gzip           bool
+	}{
+		{"gzip", true},
+		{"deflate, gzip", true},
+		{"gzip;q=0.5", true},
+		{"gzip ; q=1.0", true},
+		{"gzip;q=0", false},
+		{"gzip;q=0.0", false},
+		{"gzip; q=0.000", false},
+		{"gzip ;Q=0", false},
+		{"gzip;q=x", false},
+		{"deflate", false},
+		{"", false},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/en", nil)
+		req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if gzipped := rec.Header().Get("Content-Encoding") == "gzip"; gzipped != tc.gzip {
+			t.Errorf("expected bundle handler to respond with gzip (%v) for Accept-Encoding %q, got %v", tc.gzip, tc.acceptEncoding, gzipped)
+		}
+	}
+}
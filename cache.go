@@ -0,0 +1,73 @@
+package katolomb
+
+import (
+	"container/list"
+	"sync"
+)
+
+// messageCache is a concurrency-safe least-recently-used cache of compiled
+// messages indexed by their text. A nil or zero-sized cache keeps nothing.
+type messageCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type messageCacheEntry struct {
+	text string
+	msg  *compiledMessage
+}
+
+func newMessageCache(size int) *messageCache {
+	if size <= 0 {
+		return nil
+	}
+	return &messageCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+func (mc *messageCache) get(text string) (*compiledMessage, bool) {
+	if mc == nil {
+		return nil, false
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	e, ok := mc.items[text]
+	if !ok {
+		return nil, false
+	}
+	mc.order.MoveToFront(e)
+	return e.Value.(*messageCacheEntry).msg, true
+}
+
+func (mc *messageCache) add(text string, msg *compiledMessage) {
+	if mc == nil {
+		return
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if e, ok := mc.items[text]; ok {
+		mc.order.MoveToFront(e)
+		e.Value.(*messageCacheEntry).msg = msg
+		return
+	}
+	mc.items[text] = mc.order.PushFront(&messageCacheEntry{text: text, msg: msg})
+	if mc.order.Len() > mc.size {
+		oldest := mc.order.Back()
+		mc.order.Remove(oldest)
+		delete(mc.items, oldest.Value.(*messageCacheEntry).text)
+	}
+}
+
+func (mc *messageCache) len() int {
+	if mc == nil {
+		return 0
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.order.Len()
+}
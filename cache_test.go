@@ -0,0 +1,53 @@
+package katolomb
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMessageCache(t *testing.T) {
+	mc := newMessageCache(2)
+	a, b, c := &compiledMessage{}, &compiledMessage{}, &compiledMessage{}
+	mc.add("a", a)
+	mc.add("b", b)
+	if msg, ok := mc.get("a"); !ok || msg != a {
+		t.Errorf("expected get to return the message added for a key")
+	}
+	mc.add("c", c)
+	if _, ok := mc.get("b"); ok {
+		t.Errorf("expected the least recently used message to be evicted when exceeding the cache size")
+	}
+	if msg, ok := mc.get("a"); !ok || msg != a {
+		t.Errorf("expected recently used messages to be kept when exceeding the cache size")
+	}
+	if msg, ok := mc.get("c"); !ok || msg != c {
+		t.Errorf("expected the last added message to be kept when exceeding the cache size")
+	}
+	if l := mc.len(); l != 2 {
+		t.Errorf("expected the cache to hold 2 messages, got %d", l)
+	}
+	for _, size := range []int{0, -1} {
+		disabled := newMessageCache(size)
+		disabled.add("a", a)
+		if _, ok := disabled.get("a"); ok {
+			t.Errorf("expected a cache of size %d not to keep messages", size)
+		}
+	}
+}
+
+func TestInterpolatorCache(t *testing.T) {
+	i := NewInterpolatorWithCacheSize(10).(*interpolator)
+	props := NewTranslationProperties(map[string]string{"n": "x"})
+	for n := 0; n < 20; n++ {
+		text := "%{n} " + strconv.Itoa(n)
+		for j := 0; j < 2; j++ {
+			result, err := i.Interpolate(text, props)
+			if err != nil || result != "x "+strconv.Itoa(n) {
+				t.Errorf("expected Interpolate to return %q, got %q, %v", "x "+strconv.Itoa(n), result, err)
+			}
+		}
+	}
+	if l := i.cache.len(); l != 10 {
+		t.Errorf("expected the interpolator to keep 10 compiled messages, got %d", l)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pbanos/katolomb"
+)
+
+const checkUsage = `usage:
+  katolomb check [-separator separator] [-strip-root] <reference locale file> <locale file>...
+`
+
+func check(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	separator := fs.String("separator", ".", "separator of the keys in the locale files")
+	stripRoot := fs.Bool("strip-root", false, "strip the first segment of the keys, such as the locale root (\"en\"), before comparing them, as done by default for files whose keys are all under their locale")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fmt.Fprint(os.Stderr, checkUsage)
+		os.Exit(2)
+	}
+	translators := make(map[string]katolomb.EnumerableTranslator)
+	for _, path := range fs.Args() {
+		translator, err := readLocaleFile(path, *separator, *stripRoot)
+		if err != nil {
+			return err
+		}
+		translators[path] = translator
+	}
+	err := requireSharedKeys(fs.Arg(0), translators)
+	if err != nil {
+		return err
+	}
+	issues, err := katolomb.CheckPlaceholders(fs.Arg(0), translators)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d placeholder issues found", len(issues))
+	}
+	return nil
+}
+
+// readLocaleFile reads the locale file at path, stripping the first segment
+// of its keys when stripRoot is true or when all of them are under the file's
+// locale (e.g. "en.greeting" in "locales/en.yml").
+func readLocaleFile(path, separator string, stripRoot bool) (katolomb.EnumerableTranslator, error) {
+	locale, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	translator, err := katolomb.NewYAMLTranslatorWithSeparator(locale, separator)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	et := translator.(katolomb.EnumerableTranslator)
+	if separator == "" || !stripRoot && !underRoot(et.Keys(), localeName(path)+separator) {
+		return et, nil
+	}
+	texts := make(map[string]string)
+	props := katolomb.NewTranslationProperties(nil)
+	for _, key := range et.Keys() {
+		i := strings.Index(key, separator)
+		if i < 0 {
+			continue
+		}
+		text, err := et.Translate(key, props)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		texts[key[i+len(separator):]] = text
+	}
+	mt := katolomb.NewMemoryTranslator(separator)
+	mt.Replace(texts)
+	return mt, nil
+}
+
+// underRoot reports whether there are keys and all of them start with root.
+func underRoot(keys []string, root string) bool {
+	for _, key := range keys {
+		if !strings.HasPrefix(key, root) {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+// requireSharedKeys returns an error if the translator of the reference
+// locale file and that of any other locale file have keys but none in common,
+// which usually means their keys are under different roots.
+func requireSharedKeys(reference string, translators map[string]katolomb.EnumerableTranslator) error {
+	refKeys := make(map[string]bool)
+	for _, key := range translators[reference].Keys() {
+		refKeys[key] = true
+	}
+	for path, translator := range translators {
+		keys := translator.Keys()
+		if path == reference || len(keys) == 0 || len(refKeys) == 0 {
+			continue
+		}
+		shared := false
+		for _, key := range keys {
+			if refKeys[key] {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			return fmt.Errorf("%v and %v have no keys in common, consider using -strip-root", reference, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadLocaleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-check")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	content := []byte("es:\n  greeting: \"Hola %{name}\"\n  inbox:\n    title: Buzón\n")
+	for _, name := range []string{"es.yml", "base.yml"} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), content, 0644)
+		if err != nil {
+			t.Fatalf("expected WriteFile not to return error, got %v", err)
+		}
+	}
+	testCases := []struct {
+		file      string
+		stripRoot bool
+		expected  []string
+	}{
+		{"es.yml", false, []string{"greeting", "inbox.title"}},
+		{"es.yml", true, []string{"greeting", "inbox.title"}},
+		{"base.yml", false, []string{"es.greeting", "es.inbox.title"}},
+		{"base.yml", true, []string{"greeting", "inbox.title"}},
+	}
+	for _, tc := range testCases {
+		translator, err := readLocaleFile(filepath.Join(dir, tc.file), ".", tc.stripRoot)
+		if err != nil {
+			t.Fatalf("expected readLocaleFile not to return error, got %v", err)
+		}
+		if keys := translator.Keys(); !reflect.DeepEqual(keys, tc.expected) {
+			t.Errorf("expected readLocaleFile to return a translator with keys %v for %v when stripping root is %v, got %v", tc.expected, tc.file, tc.stripRoot, keys)
+		}
+	}
+	if _, err := readLocaleFile(filepath.Join(dir, "missing.yml"), ".", false); err == nil {
+		t.Errorf("expected readLocaleFile to return error for a missing file")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-check")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"en.yml":   "en:\n  greeting: \"Hello %{name}\"\n",
+		"es.yml":   "es:\n  greeting: \"Hola %{nombre}\"\n",
+		"fr.yml":   "fr:\n  greeting: \"Bonjour %{name}\"\n",
+		"base.yml": "de:\n  greeting: \"Hallo %{name}\"\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("expected WriteFile not to return error, got %v", err)
+		}
+	}
+	testCases := []struct {
+		args        []string
+		errNotNil   bool
+		description string
+	}{
+		{[]string{"en.yml", "fr.yml"}, false, "files with consistent placeholders under their locale roots"},
+		{[]string{"en.yml", "es.yml"}, true, "files with inconsistent placeholders under their locale roots"},
+		{[]string{"en.yml", "base.yml"}, true, "files with no keys in common"},
+		{[]string{"-strip-root", "en.yml", "base.yml"}, false, "files with roots stripped explicitly"},
+	}
+	for _, tc := range testCases {
+		args := make([]string, len(tc.args))
+		for i, arg := range tc.args {
+			args[i] = arg
+			if filepath.Ext(arg) == ".yml" {
+				args[i] = filepath.Join(dir, arg)
+			}
+		}
+		if err := check(args); (err != nil) != tc.errNotNil {
+			t.Errorf("expected check to return error (%v) for %v, got %v", tc.errNotNil, tc.description, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pbanos/katolomb"
+)
+
+const coverageUsage = `usage:
+  katolomb coverage merge [-o output] <coverage file>...
+  katolomb coverage unused [-separator separator] <locale file> <coverage file>...
+`
+
+func coverage(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, coverageUsage)
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "merge":
+		return coverageMerge(args[1:])
+	case "unused":
+		return coverageUnused(args[1:])
+	}
+	fmt.Fprint(os.Stderr, coverageUsage)
+	os.Exit(2)
+	return nil
+}
+
+func coverageMerge(args []string) error {
+	fs := flag.NewFlagSet("coverage merge", flag.ExitOnError)
+	output := fs.String("o", "", "file to write the merged coverage to (standard output by default)")
+	fs.Parse(args)
+	c, err := readCoverageFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	w := os.Stdout
+	if *output != "" {
+		w, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+	return katolomb.WriteCoverage(w, c)
+}
+
+func coverageUnused(args []string) error {
+	fs := flag.NewFlagSet("coverage unused", flag.ExitOnError)
+	separator := fs.String("separator", ".", "separator of the keys in the locale file")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fmt.Fprint(os.Stderr, coverageUsage)
+		os.Exit(2)
+	}
+	locale, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	translator, err := katolomb.NewYAMLTranslatorWithSeparator(locale, *separator)
+	if err != nil {
+		return fmt.Errorf("%v: %v", fs.Arg(0), err)
+	}
+	c, err := readCoverageFiles(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	for _, key := range c.UnusedKeys(translator.(katolomb.EnumerableTranslator)) {
+		fmt.Println(key)
+	}
+	return nil
+}
+
+func readCoverageFiles(paths []string) (katolomb.Coverage, error) {
+	merged := make(katolomb.Coverage)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		c, err := katolomb.ReadCoverage(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		merged.Merge(c)
+	}
+	return merged, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestCoverage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-coverage")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"a.json": `{"en.hello": 2, "en.bye": 1}`,
+		"b.json": `{"en.hello": 3, "en.inbox.title": 1}`,
+		"en.yml": "en:\n  hello: Hello\n  bye: Bye\n  inbox:\n    title: Inbox\n    empty: No messages\n  thanks: Thanks\n",
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("expected WriteFile not to return error, got %v", err)
+		}
+	}
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	merged := filepath.Join(dir, "merged.json")
+
+	err = coverage([]string{"merge", "-o", merged, a, b})
+	if err != nil {
+		t.Fatalf("expected coverage merge not to return error, got %v", err)
+	}
+	f, err := os.Open(merged)
+	if err != nil {
+		t.Fatalf("expected coverage merge to write the output file, got %v", err)
+	}
+	c, err := katolomb.ReadCoverage(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("expected coverage merge to write a valid coverage file, got %v", err)
+	}
+	expected := katolomb.Coverage{"en.hello": 5, "en.bye": 1, "en.inbox.title": 1}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected coverage merge to write %v, got %v", expected, c)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("expected Pipe not to return error, got %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = coverage([]string{"unused", filepath.Join(dir, "en.yml"), a, b})
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatalf("expected coverage unused not to return error, got %v", err)
+	}
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("expected ReadAll not to return error, got %v", err)
+	}
+	unused := strings.Fields(string(out))
+	if !reflect.DeepEqual(unused, []string{"en.inbox.empty", "en.thanks"}) {
+		t.Errorf("expected coverage unused to list the keys with no hits, got %v", unused)
+	}
+
+	if err := coverage([]string{"merge", filepath.Join(dir, "missing.json")}); err == nil {
+		t.Errorf("expected coverage merge to return error for a missing coverage file")
+	}
+}
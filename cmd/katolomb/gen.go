@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pbanos/katolomb"
+)
+
+const genUsage = `usage:
+  katolomb gen [-separator separator] [-package name] [-strip-root] [-trim prefix] [-o output] <locale file>
+`
+
+type genProperty struct {
+	name     string
+	param    string
+	field    string
+	optional bool
+}
+
+type genKey struct {
+	key        string
+	name       string
+	text       string
+	properties []*genProperty
+}
+
+func gen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	separator := fs.String("separator", ".", "separator of the keys in the locale file")
+	pkg := fs.String("package", "translations", "name of the generated package")
+	stripRoot := fs.Bool("strip-root", false, "strip the first segment of the keys, such as the locale root (\"en\"), from the generated names")
+	trim := fs.String("trim", "", "prefix of the keys to generate code for, trimmed from the generated names, such as the locale root (\"en.\")")
+	output := fs.String("o", "", "file to write the generated code to (standard output by default)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, genUsage)
+		os.Exit(2)
+	}
+	locale, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	translator, err := katolomb.NewYAMLTranslatorWithSeparator(locale, *separator)
+	if err != nil {
+		return fmt.Errorf("%v: %v", fs.Arg(0), err)
+	}
+	et := translator.(katolomb.EnumerableTranslator)
+	names := make(map[string]string)
+	texts := make(map[string]string)
+	props := katolomb.NewTranslationProperties(nil)
+	for _, key := range et.Keys() {
+		name := key
+		if *stripRoot && *separator != "" {
+			i := strings.Index(key, *separator)
+			if i < 0 {
+				continue
+			}
+			name = key[i+len(*separator):]
+		}
+		if !strings.HasPrefix(name, *trim) {
+			continue
+		}
+		text, err := et.Translate(key, props)
+		if err != nil {
+			return fmt.Errorf("%v: %v", fs.Arg(0), err)
+		}
+		names[key] = strings.TrimPrefix(name, *trim)
+		texts[key] = text
+	}
+	code, err := generate(*pkg, filepath.Base(fs.Arg(0)), texts, names)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(*output, code, 0644)
+}
+
+// generate returns the formatted source of a package named pkg with an
+// accessor function and a Translatable value type for each key in the texts
+// map, whose values are the texts of the keys in the source file. The names
+// of the functions and types are made from the keys' values in the names map,
+// or from the keys themselves when missing. Keys are processed in sorted
+// order so the output only depends on the input.
+func generate(pkg, source string, texts, names map[string]string) ([]byte, error) {
+	keys, err := genKeys(texts, names)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by katolomb gen from %v. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %v\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/pbanos/katolomb\"\n")
+	for _, k := range keys {
+		writeAccessor(&b, k)
+		writeValueType(&b, k)
+	}
+	code, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return code, nil
+}
+
+func genKeys(texts, names map[string]string) ([]*genKey, error) {
+	sorted := make([]string, 0, len(texts))
+	for key := range texts {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	keys := make([]*genKey, 0, len(sorted))
+	generated := make(map[string]string)
+	for _, key := range sorted {
+		source, ok := names[key]
+		if !ok {
+			source = key
+		}
+		name := identifier(source, true)
+		if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+			name = "Key" + name
+		}
+		for _, g := range []string{name, name + "Value"} {
+			if other, ok := generated[g]; ok {
+				return nil, fmt.Errorf("keys %v and %v both generate %v", strconv.Quote(other), strconv.Quote(key), g)
+			}
+			generated[g] = key
+		}
+		keys = append(keys, &genKey{key: key, name: name, text: texts[key], properties: genProperties(texts[key])})
+	}
+	return keys, nil
+}
+
+func genProperties(text string) []*genProperty {
+	var properties []*genProperty
+	byName := make(map[string]*genProperty)
+	params := map[string]bool{"t": true, "props": true}
+	fields := make(map[string]bool)
+	for _, d := range katolomb.ParseDeclarations(text) {
+		if p, ok := byName[d.Property]; ok {
+			p.optional = p.optional && d.HasDefaultValue
+			continue
+		}
+		p := &genProperty{
+			name:     d.Property,
+			param:    unique(identifier(d.Property, false), "p", params),
+			field:    unique(identifier(d.Property, true), "P", fields),
+			optional: d.HasDefaultValue,
+		}
+		byName[d.Property] = p
+		properties = append(properties, p)
+	}
+	return properties
+}
+
+func writeAccessor(b *bytes.Buffer, k *genKey) {
+	fmt.Fprintf(b, "\n// %v translates the key %v.\n", k.name, strconv.Quote(k.key))
+	if len(k.properties) > 0 {
+		fmt.Fprintf(b, "// Properties passed as nil pointers are not made available, so their\n// default values are used.\n")
+	}
+	fmt.Fprintf(b, "//\n// Its text in the source locale is:\n//\n")
+	for _, line := range strings.Split(k.text, "\n") {
+		fmt.Fprintf(b, "//\t%v\n", line)
+	}
+	fmt.Fprintf(b, "func %v(t katolomb.Translator", k.name)
+	for _, p := range k.properties {
+		if p.optional {
+			fmt.Fprintf(b, ", %v *string", p.param)
+		} else {
+			fmt.Fprintf(b, ", %v string", p.param)
+		}
+	}
+	fmt.Fprintf(b, ") (string, error) {\n")
+	if len(k.properties) == 0 {
+		fmt.Fprintf(b, "return t.Translate(%v, katolomb.NewTranslationProperties(nil))\n}\n", strconv.Quote(k.key))
+		return
+	}
+	fmt.Fprintf(b, "props := map[string]string{")
+	for _, p := range k.properties {
+		if !p.optional {
+			fmt.Fprintf(b, "%v: %v,\n", strconv.Quote(p.name), p.param)
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+	for _, p := range k.properties {
+		if p.optional {
+			fmt.Fprintf(b, "if %v != nil {\nprops[%v] = *%v\n}\n", p.param, strconv.Quote(p.name), p.param)
+		}
+	}
+	fmt.Fprintf(b, "return t.Translate(%v, katolomb.NewTranslationProperties(props))\n}\n", strconv.Quote(k.key))
+}
+
+func writeValueType(b *bytes.Buffer, k *genKey) {
+	fmt.Fprintf(b, "\n// %vValue is a katolomb.Translatable for the key %v.\n", k.name, strconv.Quote(k.key))
+	fmt.Fprintf(b, "type %vValue struct {\n", k.name)
+	for _, p := range k.properties {
+		if p.optional {
+			fmt.Fprintf(b, "%v *string\n", p.field)
+		} else {
+			fmt.Fprintf(b, "%v string\n", p.field)
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+	fmt.Fprintf(b, "\n// Translate returns the result of calling %v with the value's properties.\n", k.name)
+	fmt.Fprintf(b, "func (v %vValue) Translate(t katolomb.Translator) (string, error) {\n", k.name)
+	fmt.Fprintf(b, "return %v(t", k.name)
+	for _, p := range k.properties {
+		fmt.Fprintf(b, ", v.%v", p.field)
+	}
+	fmt.Fprintf(b, ")\n}\n")
+}
+
+// identifier returns the camel case Go identifier made of the letters and
+// digits of s, exported or not.
+func identifier(s string, exported bool) string {
+	var b strings.Builder
+	upper := exported
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = b.Len() > 0 || exported
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+		} else if b.Len() == 0 {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+	return b.String()
+}
+
+// reservedIdentifiers holds the names that generated parameters and fields
+// must not take: Go's predeclared identifiers and the imported package, which
+// parameters would shadow, and the method of the value types, which fields
+// would collide with.
+var reservedIdentifiers = func() map[string]bool {
+	reserved := map[string]bool{"katolomb": true, "Translate": true}
+	for _, name := range types.Universe.Names() {
+		reserved[name] = true
+	}
+	return reserved
+}()
+
+// unique returns name, prefixed when it is not a valid identifier or is
+// reserved and suffixed with a number when it is already used, and adds it to
+// used.
+func unique(name, prefix string, used map[string]bool) string {
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) || token.IsKeyword(name) || reservedIdentifiers[name] {
+		name = prefix + identifier(name, true)
+	}
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	texts := map[string]string{
+		"my.message":  "how are you %{timestamp}, %{name|Mister}?",
+		"goodbye":     "Bye!",
+		"user_type":   "%{type} %{user-name|someone} and %{type|again}",
+		"1st.visit":   "Welcome, %{name}",
+		"multi.lines": "First\nSecond",
+	}
+	code, err := generate("translations", "en.yml", texts, nil)
+	if err != nil {
+		t.Fatalf("expected generate not to return error, got %v", err)
+	}
+	expected := []string{
+		"// Code generated by katolomb gen from en.yml. DO NOT EDIT.",
+		"package translations",
+		"func MyMessage(t katolomb.Translator, timestamp string, name *string) (string, error) {",
+		`props := map[string]string{"timestamp": timestamp}`,
+		"type MyMessageValue struct {\n\tTimestamp string\n\tName      *string\n}",
+		"func Goodbye(t katolomb.Translator) (string, error) {",
+		`return t.Translate("goodbye", katolomb.NewTranslationProperties(nil))`,
+		"func UserType(t katolomb.Translator, pType string, userName *string) (string, error) {",
+		"func Key1stVisit(t katolomb.Translator, name string) (string, error) {",
+		"//\tFirst\n//\tSecond\n",
+		"func (v MyMessageValue) Translate(t katolomb.Translator) (string, error) {\n\treturn MyMessage(t, v.Timestamp, v.Name)\n}",
+	}
+	for _, e := range expected {
+		if !bytes.Contains(code, []byte(e)) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", e, code)
+		}
+	}
+	if strings.Index(string(code), "func Key1stVisit") > strings.Index(string(code), "func Goodbye") {
+		t.Errorf("expected generated code to follow the order of the keys")
+	}
+
+	again, err := generate("translations", "en.yml", texts, nil)
+	if err != nil || !bytes.Equal(code, again) {
+		t.Errorf("expected generate to return the same code for the same input, got different code and %v", err)
+	}
+}
+
+func TestGenerateCollision(t *testing.T) {
+	_, err := generate("translations", "en.yml", map[string]string{"my.message": "a", "my_message": "b"}, nil)
+	if err == nil {
+		t.Errorf("expected generate to return error when keys generate the same name")
+	}
+}
+
+func TestGenerateWithNames(t *testing.T) {
+	texts := map[string]string{"en.my.message": "Hello %{name}", "en.goodbye": "Bye!"}
+	names := map[string]string{"en.my.message": "my.message", "en.goodbye": "goodbye"}
+	code, err := generate("translations", "en.yml", texts, names)
+	if err != nil {
+		t.Fatalf("expected generate not to return error, got %v", err)
+	}
+	expected := []string{
+		"func MyMessage(t katolomb.Translator, name string) (string, error) {",
+		`return t.Translate("en.my.message", katolomb.NewTranslationProperties(props))`,
+		"func Goodbye(t katolomb.Translator) (string, error) {",
+		`return t.Translate("en.goodbye", katolomb.NewTranslationProperties(nil))`,
+	}
+	for _, e := range expected {
+		if !bytes.Contains(code, []byte(e)) {
+			t.Errorf("expected generated code to contain %q when names are given, got:\n%s", e, code)
+		}
+	}
+}
+
+func TestGenTrim(t *testing.T) {
+	dir := t.TempDir()
+	locale := filepath.Join(dir, "en.yml")
+	if err := ioutil.WriteFile(locale, []byte("en:\n  my:\n    message: Hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		args []string
+		name string
+	}{
+		{[]string{"-trim", "en."}, "MyMessage"},
+		{[]string{"-strip-root"}, "MyMessage"},
+		{[]string{"-strip-root", "-trim", "my."}, "Message"},
+	}
+	for _, tc := range testCases {
+		output := filepath.Join(dir, "translations.go")
+		if err := gen(append(tc.args, "-o", output, locale)); err != nil {
+			t.Fatalf("expected gen not to return error with %v, got %v", tc.args, err)
+		}
+		code, err := ioutil.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(code, []byte("func "+tc.name+"(t katolomb.Translator)")) {
+			t.Errorf("expected generated code to name the accessor after the trimmed key with %v, got:\n%s", tc.args, code)
+		}
+		if !bytes.Contains(code, []byte(`return t.Translate("en.my.message", `)) {
+			t.Errorf("expected generated code to translate the full key with %v, got:\n%s", tc.args, code)
+		}
+	}
+}
+
+// katolombStub declares the parts of the katolomb package used by generated
+// code, to type-check it without the compiled package.
+const katolombStub = `package katolomb
+
+type TranslationProperties interface {
+	Property(string) (string, error)
+}
+
+type Translator interface {
+	Translate(string, TranslationProperties) (string, error)
+}
+
+func NewTranslationProperties(map[string]string) TranslationProperties { return nil }
+`
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func typeCheck(t *testing.T, name string, src []byte, importer types.Importer) (*types.Package, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, 0)
+	if err != nil {
+		t.Fatalf("expected %v to parse, got %v", name, err)
+	}
+	conf := types.Config{Importer: importer}
+	return conf.Check(f.Name.Name, fset, []*ast.File{f}, nil)
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	stub, err := typeCheck(t, "katolomb.go", []byte(katolombStub), nil)
+	if err != nil {
+		t.Fatalf("expected the katolomb stub to type-check, got %v", err)
+	}
+	texts := map[string]string{
+		"reserved": "%{translate} %{string} %{katolomb} %{error|x} %{t} %{props} %{nil}",
+		"shadowed": "%{len} %{true|yes} %{Translate} %{type}",
+	}
+	code, err := generate("translations", "en.yml", texts, nil)
+	if err != nil {
+		t.Fatalf("expected generate not to return error, got %v", err)
+	}
+	_, err = typeCheck(t, "translations.go", code, importerFunc(func(path string) (*types.Package, error) {
+		return stub, nil
+	}))
+	if err != nil {
+		t.Errorf("expected generated code with reserved property names to type-check, got %v in:\n%s", err, code)
+	}
+}
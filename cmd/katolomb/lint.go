@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pbanos/katolomb"
+)
+
+const lintUsage = `usage:
+  katolomb lint [-separator separator] [-strip-root] [-format text|json|sarif] [-disable rules] [-forbidden terms file] [-o output] <source locale file> <locale file>...
+`
+
+func lint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	separator := fs.String("separator", ".", "separator of the keys in the locale files")
+	stripRoot := fs.Bool("strip-root", false, "strip the first segment of the keys, such as the locale root (\"en\"), before comparing them, as done by default for files whose keys are all under their locale")
+	format := fs.String("format", "text", "format of the report: text, json or sarif")
+	disable := fs.String("disable", "", "comma-separated list of rules to disable")
+	forbidden := fs.String("forbidden", "", "JSON file with lists of forbidden terms indexed by locale (locale file name without extension, such as \"es_MX\"), language (\"es\") or \"*\"")
+	output := fs.String("o", "", "file to write the report to (standard output by default)")
+	fs.Parse(args)
+	if fs.NArg() < 1 || *format != "text" && *format != "json" && *format != "sarif" {
+		fmt.Fprint(os.Stderr, lintUsage)
+		os.Exit(2)
+	}
+	translators := make(map[string]katolomb.EnumerableTranslator)
+	paths := make(map[string]string)
+	for _, path := range fs.Args() {
+		locale := localeName(path)
+		if other, ok := paths[locale]; ok {
+			return fmt.Errorf("%v and %v are both locale files for %v", other, path, locale)
+		}
+		translator, err := readLocaleFile(path, *separator, *stripRoot)
+		if err != nil {
+			return err
+		}
+		translators[locale] = translator
+		paths[locale] = path
+	}
+	err := requireSharedKeys(localeName(fs.Arg(0)), translators)
+	if err != nil {
+		return err
+	}
+	rules := katolomb.DefaultLintRules()
+	if *forbidden != "" {
+		terms, err := readForbiddenTerms(*forbidden)
+		if err != nil {
+			return err
+		}
+		rules["forbidden-terms"] = katolomb.NewForbiddenTermsRule(terms)
+	}
+	for _, name := range strings.Split(*disable, ",") {
+		delete(rules, strings.TrimSpace(name))
+	}
+	issues, err := katolomb.Lint(localeName(fs.Arg(0)), translators, rules)
+	if err != nil {
+		return err
+	}
+	for i := range issues {
+		issues[i].Locale = paths[issues[i].Locale]
+	}
+	w := os.Stdout
+	if *output != "" {
+		w, err = os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+	switch *format {
+	case "json":
+		err = katolomb.WriteLintReport(w, issues)
+	case "sarif":
+		err = katolomb.WriteLintSARIF(w, issues)
+	default:
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%v: %q: %v: %v\n", issue.Locale, issue.Key, issue.Rule, issue.Message)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d lint issues found", len(issues))
+	}
+	return nil
+}
+
+// localeName returns the locale of the locale file at path, which is its name
+// without extension (e.g. "es_MX" for "locales/es_MX.yml").
+func localeName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// readForbiddenTerms reads the lists of forbidden terms in the JSON file at
+// path, indexed by locale, language or "*".
+func readForbiddenTerms(path string) (map[string][]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	terms := make(map[string][]string)
+	err = json.Unmarshal(content, &terms)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return terms, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadForbiddenTerms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-lint")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "terms.json")
+	err = ioutil.WriteFile(path, []byte(`{"es": ["de pago"], "fr": ["gratuit"], "*": ["TODO"]}`), 0644)
+	if err != nil {
+		t.Fatalf("expected WriteFile not to return error, got %v", err)
+	}
+	terms, err := readForbiddenTerms(path)
+	if err != nil {
+		t.Fatalf("expected readForbiddenTerms not to return error, got %v", err)
+	}
+	expected := map[string][]string{
+		"es": {"de pago"},
+		"fr": {"gratuit"},
+		"*":  {"TODO"},
+	}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Errorf("expected readForbiddenTerms to return %v, got %v", expected, terms)
+	}
+	if _, err := readForbiddenTerms(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("expected readForbiddenTerms to return error for a missing file")
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-lint")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"en.yml":     "brand: Our product\n",
+		"es_MX.yml":  "brand: Nuestro producto de pago\n",
+		"terms.json": `{"es": ["de pago"]}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("expected WriteFile not to return error, got %v", err)
+		}
+	}
+	output := filepath.Join(dir, "report.json")
+	err = lint([]string{
+		"-format", "json", "-disable", "length-ratio", "-o", output,
+		"-forbidden", filepath.Join(dir, "terms.json"),
+		filepath.Join(dir, "en.yml"), filepath.Join(dir, "es_MX.yml"),
+	})
+	if err == nil {
+		t.Errorf("expected lint to return error when issues are found")
+	}
+	content, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("expected lint to write the report, got %v", err)
+	}
+	var report []map[string]string
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("expected lint to write a JSON report, got %v", err)
+	}
+	expected := []map[string]string{
+		{"rule": "forbidden-terms", "locale": filepath.Join(dir, "es_MX.yml"), "key": "brand", "message": `contains forbidden term "de pago"`},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected lint to apply language terms to regional locale files and report %v, got %v", expected, report)
+	}
+
+	err = lint([]string{filepath.Join(dir, "en.yml"), filepath.Join(dir, "en.yml")})
+	if err == nil {
+		t.Errorf("expected lint to return error when locale files share a locale")
+	}
+}
+
+func TestLintLocaleRoots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katolomb-lint")
+	if err != nil {
+		t.Fatalf("expected TempDir not to return error, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"en.yml":   "en:\n  settings: Settings\n",
+		"es.yml":   "es:\n  settings: Settings\n",
+		"base.yml": "de:\n  settings: Einstellungen\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("expected WriteFile not to return error, got %v", err)
+		}
+	}
+	output := filepath.Join(dir, "report.json")
+	err = lint([]string{"-format", "json", "-o", output, filepath.Join(dir, "en.yml"), filepath.Join(dir, "es.yml")})
+	if err == nil {
+		t.Errorf("expected lint to return error when issues are found in files with locale roots")
+	}
+	content, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("expected lint to write the report, got %v", err)
+	}
+	var report []map[string]string
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("expected lint to write a JSON report, got %v", err)
+	}
+	expected := []map[string]string{
+		{"rule": "untranslated", "locale": filepath.Join(dir, "es.yml"), "key": "settings", "message": "identical to the source"},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected lint to compare keys under locale roots and report %v, got %v", expected, report)
+	}
+	if err := lint([]string{filepath.Join(dir, "en.yml"), filepath.Join(dir, "base.yml")}); err == nil {
+		t.Errorf("expected lint to return error when locale files have no keys in common")
+	}
+}
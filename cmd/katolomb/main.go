@@ -0,0 +1,52 @@
+// Command katolomb provides tooling to manage katolomb translations.
+//
+// Usage:
+//   katolomb <command> [arguments]
+//
+// The commands are:
+//   check            check the interpolation declarations of locale files
+//   coverage merge   merge coverage files from multiple instances
+//   coverage unused  list the keys of a locale file with no hits in coverage files
+//   gen              generate type-safe accessors for the keys of a locale file
+//   lint             check the translations of locale files with lint rules
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: katolomb <command> [arguments]
+
+The commands are:
+  check            check the interpolation declarations of locale files
+  coverage merge   merge coverage files from multiple instances
+  coverage unused  list the keys of a locale file with no hits in coverage files
+  gen              generate type-safe accessors for the keys of a locale file
+  lint             check the translations of locale files with lint rules
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "check":
+		err = check(os.Args[2:])
+	case "coverage":
+		err = coverage(os.Args[2:])
+	case "gen":
+		err = gen(os.Args[2:])
+	case "lint":
+		err = lint(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "katolomb %v: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+package katolomb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ContextTranslator is the interface that wraps the TranslateContext method.
+//
+// TranslateContext takes a context.Context, a string and a
+// TranslationProperties and returns a string with a translation and an error.
+// Implementations should honour the context's cancellation and deadline.
+type ContextTranslator interface {
+	TranslateContext(context.Context, string, TranslationProperties) (string, error)
+}
+
+// ContextTranslatorFunc wraps a function with the ContextTranslator's
+// TranslateContext method signature to satisfy the ContextTranslator
+// interface. It also satisfies the Translator interface, translating with
+// context.Background().
+type ContextTranslatorFunc func(context.Context, string, TranslationProperties) (string, error)
+
+type contextKey int
+
+const (
+	localeContextKey contextKey = iota
+	propertiesContextKey
+)
+
+// TranslateContext calls the function with the received context, key and
+// properties parameters and returns the result.
+func (ctf ContextTranslatorFunc) TranslateContext(ctx context.Context, key string, properties TranslationProperties) (string, error) {
+	return ctf(ctx, key, properties)
+}
+
+// Translate calls the function with context.Background() and the received key
+// and properties parameters and returns the result.
+func (ctf ContextTranslatorFunc) Translate(key string, properties TranslationProperties) (string, error) {
+	return ctf(context.Background(), key, properties)
+}
+
+// AsContextTranslator takes a Translator and returns it as a
+// ContextTranslator. Translators already implementing ContextTranslator are
+// returned as they are; otherwise the result's TranslateContext method
+// returns an error wrapping the context's error when it is already done, and
+// calls the Translator's Translate method otherwise.
+func AsContextTranslator(translator Translator) ContextTranslator {
+	if ct, ok := translator.(ContextTranslator); ok {
+		return ct
+	}
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), err)
+		}
+		return translator.Translate(key, props)
+	})
+}
+
+// AsTranslator takes a ContextTranslator and returns it as a Translator.
+// ContextTranslators already implementing Translator are returned as they
+// are; otherwise the result's Translate method calls the ContextTranslator's
+// TranslateContext method with context.Background().
+func AsTranslator(translator ContextTranslator) Translator {
+	if t, ok := translator.(Translator); ok {
+		return t
+	}
+	return TranslatorFunc(func(key string, props TranslationProperties) (string, error) {
+		return translator.TranslateContext(context.Background(), key, props)
+	})
+}
+
+// WithLocale returns a copy of the context parameter carrying the given
+// locale, to be retrieved with LocaleFromContext.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey, locale)
+}
+
+// LocaleFromContext returns the locale carried by the context parameter and
+// whether it carries one.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeContextKey).(string)
+	return locale, ok
+}
+
+// WithProperties returns a copy of the context parameter carrying the given
+// TranslationProperties, to be retrieved with PropertiesFromContext.
+func WithProperties(ctx context.Context, props TranslationProperties) context.Context {
+	return context.WithValue(ctx, propertiesContextKey, props)
+}
+
+// PropertiesFromContext returns the TranslationProperties carried by the
+// context parameter and whether it carries any.
+func PropertiesFromContext(ctx context.Context) (TranslationProperties, bool) {
+	props, ok := ctx.Value(propertiesContextKey).(TranslationProperties)
+	return props, ok
+}
+
+// NewContextTranslationProperties takes a context and a TranslationProperties
+// and returns a TranslationProperties that provides the properties of the
+// TranslationProperties parameter, falling back to those carried by the
+// context when a property is not available. The TranslationProperties
+// parameter may be nil.
+func NewContextTranslationProperties(ctx context.Context, ps TranslationProperties) TranslationProperties {
+	cps, ok := PropertiesFromContext(ctx)
+	if !ok {
+		if ps == nil {
+			return NewTranslationProperties(nil)
+		}
+		return ps
+	}
+	if ps == nil {
+		return cps
+	}
+	return TranslationPropertiesFunc(func(p string) (string, error) {
+		v, err := ps.Property(p)
+		if err != nil {
+			return cps.Property(p)
+		}
+		return v, nil
+	})
+}
@@ -0,0 +1,155 @@
+package katolomb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+type contextKey string
+
+func TestContextTranslatorAdapters(t *testing.T) {
+	plain := katolomb.TranslatorFunc(func(key string, p katolomb.TranslationProperties) (string, error) {
+		return "translated " + key, nil
+	})
+	ct := katolomb.AsContextTranslator(plain)
+	result, err := ct.TranslateContext(context.Background(), "my.key", nil)
+	if err != nil || result != "translated my.key" {
+		t.Errorf("expected adapted TranslateContext to return %q, got %q, %v", "translated my.key", result, err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ct.TranslateContext(canceled, "my.key", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected adapted TranslateContext to return the context error for a done context, got %v", err)
+	}
+	contextual := katolomb.ContextTranslatorFunc(func(ctx context.Context, key string, p katolomb.TranslationProperties) (string, error) {
+		v, _ := ctx.Value(contextKey("k")).(string)
+		return key + v, nil
+	})
+	result, err = katolomb.AsContextTranslator(contextual).TranslateContext(context.WithValue(context.Background(), contextKey("k"), "!"), "key", nil)
+	if err != nil || result != "key!" {
+		t.Errorf("expected AsContextTranslator to keep ContextTranslators as they are, got %q, %v", result, err)
+	}
+	onlyContext := struct{ katolomb.ContextTranslator }{contextual}
+	result, err = katolomb.AsTranslator(onlyContext).Translate("key", nil)
+	if err != nil || result != "key" {
+		t.Errorf("expected adapted Translate to return %q, got %q, %v", "key", result, err)
+	}
+}
+
+func TestContextHelpers(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := katolomb.LocaleFromContext(ctx); ok {
+		t.Errorf("expected LocaleFromContext to report no locale for an empty context")
+	}
+	if _, ok := katolomb.PropertiesFromContext(ctx); ok {
+		t.Errorf("expected PropertiesFromContext to report no properties for an empty context")
+	}
+	ctx = katolomb.WithLocale(ctx, "es")
+	if locale, ok := katolomb.LocaleFromContext(ctx); !ok || locale != "es" {
+		t.Errorf("expected LocaleFromContext to return es, got %q, %v", locale, ok)
+	}
+	ctx = katolomb.WithProperties(ctx, katolomb.NewTranslationProperties(map[string]string{"name": "Kató", "lang": "hu"}))
+	props := katolomb.NewContextTranslationProperties(ctx, katolomb.NewTranslationProperties(map[string]string{"name": "Frida"}))
+	testCases := []struct {
+		property  string
+		value     string
+		errNotNil bool
+	}{
+		{"name", "Frida", false},
+		{"lang", "hu", false},
+		{"missing", "", true},
+	}
+	for _, tc := range testCases {
+		v, err := props.Property(tc.property)
+		if (err != nil) != tc.errNotNil || v != tc.value {
+			t.Errorf("expected Property to return %q (error %v) for %v, got %q, %v", tc.value, tc.errNotNil, tc.property, v, err)
+		}
+	}
+	if v, err := katolomb.NewContextTranslationProperties(ctx, nil).Property("lang"); err != nil || v != "hu" {
+		t.Errorf("expected context properties to be used when no properties are given, got %q, %v", v, err)
+	}
+	if _, err := katolomb.NewContextTranslationProperties(context.Background(), nil).Property("lang"); err == nil {
+		t.Errorf("expected no properties to be available without properties")
+	}
+}
+
+func TestWrappersTranslateContext(t *testing.T) {
+	var seen context.Context
+	contextual := katolomb.ContextTranslatorFunc(func(ctx context.Context, key string, p katolomb.TranslationProperties) (string, error) {
+		seen = ctx
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if key == "missing" {
+			return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), katolomb.ErrKeyNotFound)
+		}
+		return "Hello %{name}", nil
+	})
+	props := katolomb.NewTranslationProperties(map[string]string{"name": "Kató"})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx := katolomb.WithLocale(context.Background(), "en")
+	testCases := []struct {
+		translator  katolomb.Translator
+		ctx         context.Context
+		key         string
+		result      string
+		errTarget   error
+		description string
+	}{
+		{katolomb.NewDefaultTranslator("default", contextual), ctx, "greeting", "Hello %{name}", nil, "default translator"},
+		{katolomb.NewDefaultTranslator("default", contextual), ctx, "missing", "default", nil, "default translator with a missing key"},
+		{katolomb.NewDefaultTranslator("default", contextual), canceled, "greeting", "", context.Canceled, "default translator with a done context"},
+		{katolomb.NewKeyAsDefaultTranslator(contextual), ctx, "missing", "missing", nil, "key-as-default translator with a missing key"},
+		{katolomb.NewKeyAsDefaultTranslator(contextual), canceled, "missing", "", context.Canceled, "key-as-default translator with a done context"},
+		{katolomb.NewInterpolatedTranslator(contextual, katolomb.NewInterpolator()), ctx, "greeting", "Hello Kató", nil, "interpolated translator"},
+		{katolomb.NewInterpolatedTranslator(contextual, katolomb.NewInterpolator()), canceled, "greeting", "", context.Canceled, "interpolated translator with a done context"},
+		{katolomb.NewReferenceTranslator(contextual), ctx, "greeting", "Hello %{name}", nil, "reference translator"},
+		{katolomb.NewReferenceTranslator(contextual), canceled, "greeting", "", context.Canceled, "reference translator with a done context"},
+		{katolomb.NewSelectTranslator(contextual, "gender"), ctx, "greeting", "Hello %{name}", nil, "select translator"},
+		{katolomb.NewSelectTranslator(contextual, "gender"), canceled, "greeting", "", context.Canceled, "select translator with a done context"},
+		{katolomb.NewPseudoTranslator(contextual), ctx, "greeting", "[Ĥéļļö %{name}~~]", nil, "pseudo translator"},
+		{katolomb.NewPseudoTranslator(contextual), canceled, "greeting", "", context.Canceled, "pseudo translator with a done context"},
+		{katolomb.NewMissingKeyRecorder(contextual, "en"), ctx, "greeting", "Hello %{name}", nil, "missing key recorder"},
+		{katolomb.NewCoverageTranslator(contextual), ctx, "greeting", "Hello %{name}", nil, "coverage translator"},
+		{katolomb.NewCoverageTranslator(contextual), canceled, "greeting", "", context.Canceled, "coverage translator with a done context"},
+	}
+	for _, tc := range testCases {
+		ct, ok := tc.translator.(katolomb.ContextTranslator)
+		if !ok {
+			t.Fatalf("expected the %v to be a ContextTranslator", tc.description)
+		}
+		seen = nil
+		result, err := ct.TranslateContext(tc.ctx, tc.key, props)
+		if tc.errTarget == nil && err != nil || tc.errTarget != nil && !errors.Is(err, tc.errTarget) {
+			t.Errorf("expected TranslateContext on the %v to return error %v, got %v", tc.description, tc.errTarget, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected TranslateContext on the %v to return %v, got %v", tc.description, strconv.Quote(tc.result), strconv.Quote(result))
+		}
+		if seen != tc.ctx {
+			t.Errorf("expected TranslateContext on the %v to pass the context to the wrapped translator", tc.description)
+		}
+	}
+}
+
+func TestMissingKeyRecorderTranslateContext(t *testing.T) {
+	mkr := katolomb.NewMissingKeyRecorder(katolomb.NewMemoryTranslator("."), "en")
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mkr.TranslateContext(canceled, "greeting", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected TranslateContext to return an error wrapping context.Canceled with a done context, got %v", err)
+	}
+	if snapshot := mkr.Snapshot(); len(snapshot) != 0 {
+		t.Errorf("expected TranslateContext not to record keys failing because the context is done, got %v", snapshot)
+	}
+	mkr.TranslateContext(context.Background(), "greeting", nil)
+	if snapshot := mkr.Snapshot(); len(snapshot) != 1 || snapshot[0].Key != "greeting" {
+		t.Errorf("expected TranslateContext to record missing keys, got %v", snapshot)
+	}
+}
@@ -0,0 +1,113 @@
+package katolomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Coverage holds the number of successful translations of each key.
+type Coverage map[string]uint64
+
+// CoverageTranslator is a Translator that wraps another Translator to count
+// its successful translations of each key. It is safe for concurrent use, and
+// counting hits of already seen keys takes no locks.
+type CoverageTranslator struct {
+	translator Translator
+	ct         ContextTranslator
+	counters   sync.Map
+}
+
+// NewCoverageTranslator takes a Translator and returns a CoverageTranslator
+// wrapping it.
+func NewCoverageTranslator(translator Translator) *CoverageTranslator {
+	return &CoverageTranslator{translator: translator, ct: AsContextTranslator(translator)}
+}
+
+// Translate returns the result of the wrapped Translator's Translate method,
+// counting a hit for the key when it does not return an error.
+func (ct *CoverageTranslator) Translate(key string, props TranslationProperties) (string, error) {
+	return ct.TranslateContext(context.Background(), key, props)
+}
+
+// TranslateContext behaves like Translate, passing the context to the wrapped
+// Translator (see AsContextTranslator).
+func (ct *CoverageTranslator) TranslateContext(ctx context.Context, key string, props TranslationProperties) (string, error) {
+	t, err := ct.ct.TranslateContext(ctx, key, props)
+	if err != nil {
+		return t, err
+	}
+	counter, ok := ct.counters.Load(key)
+	if !ok {
+		counter, _ = ct.counters.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(counter.(*uint64), 1)
+	return t, nil
+}
+
+// Coverage returns the number of hits counted so far for each key.
+func (ct *CoverageTranslator) Coverage() Coverage {
+	c := make(Coverage)
+	ct.counters.Range(func(key, counter interface{}) bool {
+		c[key.(string)] = atomic.LoadUint64(counter.(*uint64))
+		return true
+	})
+	return c
+}
+
+// UnusedKeys returns the sorted list of keys of the wrapped Translator with no
+// hits counted so far. It returns nil if the wrapped Translator is not an
+// EnumerableTranslator.
+func (ct *CoverageTranslator) UnusedKeys() []string {
+	et, ok := ct.translator.(EnumerableTranslator)
+	if !ok {
+		return nil
+	}
+	return ct.Coverage().UnusedKeys(et)
+}
+
+// Merge adds the hits in the other Coverage to the Coverage.
+func (c Coverage) Merge(other Coverage) {
+	for key, hits := range other {
+		c[key] += hits
+	}
+}
+
+// UnusedKeys returns the sorted list of keys of the EnumerableTranslator
+// parameter with no hits in the Coverage.
+func (c Coverage) UnusedKeys(et EnumerableTranslator) []string {
+	unused := []string{}
+	for _, key := range et.Keys() {
+		if c[key] == 0 {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// WriteCoverage writes the Coverage parameter to w as a JSON object with keys
+// as attributes and their hits as values.
+func WriteCoverage(w io.Writer, c Coverage) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(c)
+	if err != nil {
+		return fmt.Errorf("writing coverage: %v", err)
+	}
+	return nil
+}
+
+// ReadCoverage reads a Coverage written by WriteCoverage from r.
+func ReadCoverage(r io.Reader) (Coverage, error) {
+	c := make(Coverage)
+	err := json.NewDecoder(r).Decode(&c)
+	if err != nil {
+		return nil, fmt.Errorf("reading coverage: %v", err)
+	}
+	return c, nil
+}
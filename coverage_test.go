@@ -0,0 +1,74 @@
+package katolomb_test
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestCoverageTranslator(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"a": "A", "b": {"c": "C", "d": "D"}, "e": "E"}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	ct := katolomb.NewCoverageTranslator(yt)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				ct.Translate("a", nil)
+				ct.Translate("b.c", nil)
+				ct.Translate("missing", nil)
+			}
+		}()
+	}
+	wg.Wait()
+	expected := katolomb.Coverage{"a": 800, "b.c": 800}
+	if c := ct.Coverage(); !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected Coverage to return %v, got %v", expected, c)
+	}
+	if unused := ct.UnusedKeys(); !reflect.DeepEqual(unused, []string{"b.d", "e"}) {
+		t.Errorf("expected UnusedKeys to return [b.d e], got %v", unused)
+	}
+	plain := katolomb.NewCoverageTranslator(katolomb.TranslatorFunc(func(k string, p katolomb.TranslationProperties) (string, error) {
+		return k, nil
+	}))
+	if unused := plain.UnusedKeys(); unused != nil {
+		t.Errorf("expected UnusedKeys to return nil when wrapping a non-enumerable Translator, got %v", unused)
+	}
+}
+
+func TestCoverageMergeAndPersistence(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"a": "A", "b": "B", "c": "C"}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	first := katolomb.Coverage{"a": 2}
+	second := katolomb.Coverage{"a": 1, "b": 5}
+	var buf bytes.Buffer
+	if err := katolomb.WriteCoverage(&buf, second); err != nil {
+		t.Fatalf("expected WriteCoverage not to return error, got %v", err)
+	}
+	read, err := katolomb.ReadCoverage(&buf)
+	if err != nil {
+		t.Fatalf("expected ReadCoverage not to return error, got %v", err)
+	}
+	if !reflect.DeepEqual(read, second) {
+		t.Errorf("expected ReadCoverage to return the written coverage %v, got %v", second, read)
+	}
+	first.Merge(read)
+	if expected := (katolomb.Coverage{"a": 3, "b": 5}); !reflect.DeepEqual(first, expected) {
+		t.Errorf("expected Merge to result in %v, got %v", expected, first)
+	}
+	if unused := first.UnusedKeys(yt.(katolomb.EnumerableTranslator)); !reflect.DeepEqual(unused, []string{"c"}) {
+		t.Errorf("expected UnusedKeys to return [c], got %v", unused)
+	}
+	if _, err := katolomb.ReadCoverage(bytes.NewBufferString("not json")); err == nil {
+		t.Errorf("expected ReadCoverage to return error for invalid input")
+	}
+}
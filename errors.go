@@ -0,0 +1,147 @@
+package katolomb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrKeyNotFound is returned (wrapped) by translators when no translation is
+// available for the requested key.
+var ErrKeyNotFound = errors.New("not found")
+
+// ErrIncompletePath is returned (wrapped) by translators when the requested
+// key leads to a group of translations instead of a single translation.
+var ErrIncompletePath = errors.New("incomplete path")
+
+// ErrPropertyMissing is returned (wrapped) by TranslationProperties when the
+// requested property is not available.
+var ErrPropertyMissing = errors.New("property not available")
+
+// ErrReferenceCycle is returned (wrapped) by translators resolving key
+// references when a translation references itself, directly or through other
+// translations.
+var ErrReferenceCycle = errors.New("reference cycle")
+
+// ErrReferenceDepth is returned (wrapped) by translators resolving key
+// references when references are nested deeper than allowed.
+var ErrReferenceDepth = errors.New("maximum reference depth exceeded")
+
+// InterpolationError describes a failure to interpolate a property into a
+// text.
+//
+// Key holds the translation key whose translation was being interpolated, and
+// it is only set when the error was produced through a translator such as the
+// one returned by NewInterpolatedTranslator. Property holds the name of the
+// property that could not be interpolated, Text the text being interpolated
+// and Err the underlying error.
+type InterpolationError struct {
+	Key      string
+	Property string
+	Text     string
+	Err      error
+}
+
+// Error returns a description of the interpolation error, prefixed by the
+// translation key when available.
+func (ie *InterpolationError) Error() string {
+	msg := fmt.Sprintf("interpolating %v: property %v: %v", strconv.Quote(ie.Text), strconv.Quote(ie.Property), ie.Err)
+	if ie.Key != "" {
+		msg = fmt.Sprintf("translating %v: %v", strconv.Quote(ie.Key), msg)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error of the interpolation error.
+func (ie *InterpolationError) Unwrap() error {
+	return ie.Err
+}
+
+// TranslatableError is an error that can be shown to end users in their
+// language. It is both an error, whose message is in the source language, and
+// a Translatable, translated through its Message.
+//
+// Message is the Message translating the error, Text the untranslated
+// description of the error, and Err the error it wraps, if any.
+type TranslatableError struct {
+	Message Message
+	Text    string
+	Err     error
+}
+
+// NewTranslatableError takes a Message and an untranslated text and returns a
+// *TranslatableError with them. The text may be empty, in which case the
+// Message's key is used to describe the error.
+func NewTranslatableError(msg Message, text string) *TranslatableError {
+	return &TranslatableError{Message: msg, Text: text}
+}
+
+// WrapTranslatableError takes an error, a Message and an untranslated text
+// and returns a *TranslatableError with them wrapping the error.
+func WrapTranslatableError(err error, msg Message, text string) *TranslatableError {
+	return &TranslatableError{Message: msg, Text: text, Err: err}
+}
+
+// Error returns the untranslated text of the error, or its Message's key if
+// it has none, followed by the message of the wrapped error if any.
+func (te *TranslatableError) Error() string {
+	msg := te.Text
+	if msg == "" {
+		msg = te.Message.Key()
+	}
+	if te.Err != nil {
+		msg = fmt.Sprintf("%v: %v", msg, te.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the error wrapped by the error.
+func (te *TranslatableError) Unwrap() error {
+	return te.Err
+}
+
+// Translate returns the translation of the error's Message by the Translator
+// parameter.
+func (te *TranslatableError) Translate(translator Translator) (string, error) {
+	return te.Message.Translate(translator)
+}
+
+// TranslateError takes a Translator and an error and returns the best
+// localised message for the error: the translation of the outermost error in
+// its chain (as walked by errors.Unwrap, including errors joining several
+// errors) that is a Translatable and that the Translator can translate, and
+// true. If there is none, the error's message and false are returned.
+func TranslateError(translator Translator, err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	if t, ok := translateErrorChain(translator, err); ok {
+		return t, true
+	}
+	return err.Error(), false
+}
+
+func translateErrorChain(translator Translator, err error) (string, bool) {
+	if tr, ok := err.(Translatable); ok {
+		t, terr := tr.Translate(translator)
+		if terr == nil {
+			return t, true
+		}
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			return translateErrorChain(translator, next)
+		}
+	case interface{ Unwrap() []error }:
+		for _, next := range e.Unwrap() {
+			if next == nil {
+				continue
+			}
+			if t, ok := translateErrorChain(translator, next); ok {
+				return t, true
+			}
+		}
+	}
+	return "", false
+}
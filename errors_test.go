@@ -0,0 +1,139 @@
+package katolomb_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestErrors(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`---
+greetings:
+  hello: "Hello %{name}!"`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(yt, katolomb.NewInterpolator())
+	noProps := katolomb.NewTranslationProperties(map[string]string{})
+	customErr := fmt.Errorf("custom error")
+	customInterpolator := katolomb.InterpolatorFunc(func(string, katolomb.TranslationProperties) (string, error) {
+		return "", customErr
+	})
+	testCases := []struct {
+		translator  katolomb.Translator
+		key         string
+		target      error
+		description string
+	}{
+		{translator, "greetings.bye", katolomb.ErrKeyNotFound, "translating a missing key"},
+		{translator, "greetings.hello.world", katolomb.ErrKeyNotFound, "translating a key beyond a translation"},
+		{translator, "greetings", katolomb.ErrIncompletePath, "translating a key leading to a group of translations"},
+		{translator, "greetings.hello", katolomb.ErrPropertyMissing, "interpolating a missing property"},
+		{katolomb.NewInterpolatedTranslator(yt, customInterpolator), "greetings.hello", customErr, "interpolating with a custom interpolator error"},
+	}
+	for _, tc := range testCases {
+		_, err := tc.translator.Translate(tc.key, noProps)
+		if !errors.Is(err, tc.target) {
+			t.Errorf("expected Translate to return an error matching %v when %v, got %v", tc.target, tc.description, err)
+		}
+	}
+}
+
+func TestInterpolationError(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`hello: "Hello %{name}!"`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	noProps := katolomb.NewTranslationProperties(map[string]string{})
+	testCases := []struct {
+		err         error
+		key         string
+		description string
+	}{
+		{func() error { _, err := katolomb.NewInterpolator().Interpolate("Hello %{name}!", noProps); return err }(), "", "interpolating directly"},
+		{func() error {
+			_, err := katolomb.NewInterpolatedTranslator(yt, katolomb.NewInterpolator()).Translate("hello", noProps)
+			return err
+		}(), "hello", "interpolating through NewInterpolatedTranslator"},
+	}
+	for _, tc := range testCases {
+		var ie *katolomb.InterpolationError
+		if !errors.As(tc.err, &ie) {
+			t.Errorf("expected an *InterpolationError when %v, got %v", tc.description, tc.err)
+			continue
+		}
+		if ie.Key != tc.key {
+			t.Errorf("expected InterpolationError's Key to be %q when %v, got %q", tc.key, tc.description, ie.Key)
+		}
+		if ie.Property != "name" {
+			t.Errorf("expected InterpolationError's Property to be %q when %v, got %q", "name", tc.description, ie.Property)
+		}
+		if ie.Text != "Hello %{name}!" {
+			t.Errorf("expected InterpolationError's Text to be %q when %v, got %q", "Hello %{name}!", tc.description, ie.Text)
+		}
+		if !errors.Is(ie, katolomb.ErrPropertyMissing) {
+			t.Errorf("expected InterpolationError to wrap ErrPropertyMissing when %v", tc.description)
+		}
+	}
+}
+
+type joinedErrors []error
+
+func (je joinedErrors) Error() string {
+	return fmt.Sprintf("%d errors", len(je))
+}
+
+func (je joinedErrors) Unwrap() []error {
+	return je
+}
+
+func TestTranslatableError(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`---
+errors:
+  insufficient_funds: "Saldo insuficiente: faltan %{amount}"
+  payment_failed: "El pago ha fallado"`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(yt, katolomb.NewInterpolator())
+	cause := errors.New("connection reset")
+	funds := katolomb.NewTranslatableError(katolomb.NewMessage("errors.insufficient_funds").With("amount", "5€"), "insufficient funds")
+	payment := katolomb.WrapTranslatableError(funds, katolomb.NewMessage("errors.payment_failed"), "payment failed")
+	unknown := katolomb.WrapTranslatableError(cause, katolomb.NewMessage("errors.unknown"), "")
+
+	if msg := payment.Error(); msg != "payment failed: insufficient funds" {
+		t.Errorf("expected Error to return the text followed by the wrapped error, got %q", msg)
+	}
+	if msg := unknown.Error(); msg != "errors.unknown: connection reset" {
+		t.Errorf("expected Error to fall back to the key without text, got %q", msg)
+	}
+	if !errors.Is(payment, funds) || !errors.Is(unknown, cause) {
+		t.Errorf("expected translatable errors to unwrap to the wrapped errors")
+	}
+	var te *katolomb.TranslatableError
+	if !errors.As(fmt.Errorf("charging: %w", funds), &te) || te != funds {
+		t.Errorf("expected errors.As to find the translatable error in a chain")
+	}
+
+	testCases := []struct {
+		err         error
+		expected    string
+		translated  bool
+		description string
+	}{
+		{payment, "El pago ha fallado", true, "the outermost error can be translated"},
+		{fmt.Errorf("charging: %w", payment), "El pago ha fallado", true, "a translatable error is wrapped by a plain error"},
+		{katolomb.WrapTranslatableError(funds, katolomb.NewMessage("errors.unknown"), "unknown"), "Saldo insuficiente: faltan 5€", true, "only an inner error can be translated"},
+		{joinedErrors{cause, funds}, "Saldo insuficiente: faltan 5€", true, "a joined error can be translated"},
+		{unknown, "errors.unknown: connection reset", false, "no error in the chain can be translated"},
+		{cause, "connection reset", false, "the error is not translatable"},
+	}
+	for _, tc := range testCases {
+		result, translated := katolomb.TranslateError(translator, tc.err)
+		if result != tc.expected || translated != tc.translated {
+			t.Errorf("expected TranslateError to return %q and %v when %v, got %q and %v", tc.expected, tc.translated, tc.description, result, translated)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package katolomb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filter is the type of functions that post-process interpolated values. It
+// takes the value and the arguments given to the filter in the interpolation
+// declaration, and returns the processed value and an error.
+type Filter func(value string, args ...string) (string, error)
+
+// Filters is a registry of filters indexed by the name used to apply them in
+// interpolation declarations.
+type Filters map[string]Filter
+
+type filterCall struct {
+	name   string
+	filter Filter
+	args   []string
+}
+
+type filterInterpolator struct {
+	filters Filters
+	cache   *messageCache
+}
+
+// DefaultFilters returns a new Filters registry with the following filters:
+//   * upcase: converts the value to upper case.
+//   * downcase: converts the value to lower case.
+//   * capitalize: converts the first character of the value to upper case.
+//   * trim: removes leading and trailing white space from the value.
+//   * truncate:<n>[,<omission>]: keeps the first n characters of values
+//   longer than n characters, followed by the omission ("..." by default).
+//   * default:<value>: replaces empty values, including those of properties
+//   that are not available, with the given value.
+func DefaultFilters() Filters {
+	return Filters{
+		"upcase": func(v string, args ...string) (string, error) {
+			return strings.ToUpper(v), nil
+		},
+		"downcase": func(v string, args ...string) (string, error) {
+			return strings.ToLower(v), nil
+		},
+		"capitalize": func(v string, args ...string) (string, error) {
+			r, size := utf8.DecodeRuneInString(v)
+			if size == 0 {
+				return v, nil
+			}
+			return string(unicode.ToUpper(r)) + v[size:], nil
+		},
+		"trim": func(v string, args ...string) (string, error) {
+			return strings.TrimSpace(v), nil
+		},
+		"truncate": func(v string, args ...string) (string, error) {
+			if len(args) == 0 || len(args) > 2 {
+				return "", fmt.Errorf("truncate takes 1 or 2 arguments, got %d", len(args))
+			}
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("truncate length %v is not a non-negative integer", strconv.Quote(args[0]))
+			}
+			omission := "..."
+			if len(args) == 2 {
+				omission = args[1]
+			}
+			runes := []rune(v)
+			if len(runes) <= n {
+				return v, nil
+			}
+			return string(runes[:n]) + omission, nil
+		},
+		"default": func(v string, args ...string) (string, error) {
+			if len(args) != 1 {
+				return "", fmt.Errorf("default takes 1 argument, got %d", len(args))
+			}
+			if v == "" {
+				return args[0], nil
+			}
+			return v, nil
+		},
+	}
+}
+
+// NewFilterInterpolator returns an Interpolator that can detect and
+// interpolate interpolation declarations with the following format:
+//   %{<property name> | <filter>:<argument>,<argument> | <filter> ...}
+// where:
+//   * <property name> is the name of the property to interpolate, with any
+//   surrounding white space ignored.
+//   * <filter> is the name of a filter in the given registry, which is applied
+//   with the given arguments to the result of the previous filter (or the
+//   property's value for the first one). Filters are optional and so are
+//   their arguments. Arguments may be quoted as Go string literals (e.g.
+//   default:"n/a") to contain white space, commas, pipes or closing braces.
+//
+// This format keeps backward compatibility with the one of the Interpolator
+// returned by NewInterpolator: declarations are only taken as using filters
+// when everything after their first | is a chain of filters in the registry.
+// Any other declaration, such as %{name|Mister} or %{name|upcase|Mister}, is
+// interpolated as by NewInterpolator, with its property name taken verbatim
+// and the text after its first | as default value. Filters thus take
+// precedence, so a default value that is a chain of filters must be declared
+// with the default filter instead.
+//
+// When the property is not available, the declaration is interpolated with
+// an empty value run through its filters when it uses the "default" filter,
+// or else with the text after its first | as NewInterpolator would do (e.g.
+// %{name|upcase} is interpolated as "upcase"). Declarations with no | produce
+// an *InterpolationError then, and errors returned by filters are returned
+// wrapped in an *InterpolationError.
+//
+// Texts are parsed once and cached like with the Interpolator returned by
+// NewInterpolator. The registry is copied, so later changes to it do not
+// affect the returned Interpolator.
+func NewFilterInterpolator(filters Filters) Interpolator {
+	registry := make(Filters, len(filters))
+	for name, f := range filters {
+		registry[name] = f
+	}
+	return &filterInterpolator{filters: registry, cache: newMessageCache(DefaultInterpolatorCacheSize)}
+}
+
+// Interpolate takes a string and a TranslationProperties and returns the string
+// with any interpolation declarations replaced with the right properties in
+// the TranslationProperties parameter, run through the declared filters.
+func (fi *filterInterpolator) Interpolate(text string, properties TranslationProperties) (string, error) {
+	msg, ok := fi.cache.get(text)
+	if !ok {
+		msg = fi.compileMessage(text)
+		fi.cache.add(text, msg)
+	}
+	if msg.interpolations == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, segment := range msg.segments {
+		if !segment.placeholder {
+			b.WriteString(segment.literal)
+			continue
+		}
+		value, err := properties.Property(segment.property)
+		if err != nil {
+			switch {
+			case segment.hasDefaultFilter():
+				value = ""
+			case segment.hasDefaultValue:
+				b.WriteString(segment.defaultValue)
+				continue
+			default:
+				return "", &InterpolationError{Property: segment.property, Text: text, Err: err}
+			}
+		}
+		for _, call := range segment.filters {
+			value, err = call.filter(value, call.args...)
+			if err != nil {
+				return "", &InterpolationError{Property: segment.property, Text: text, Err: fmt.Errorf("applying filter %v: %w", strconv.Quote(call.name), err)}
+			}
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+// compileMessage splits the text into literal segments and interpolation
+// declarations. Declarations using filters may hold closing braces in quoted
+// arguments, while legacy declarations end at their first closing brace, as
+// with NewInterpolator.
+func (fi *filterInterpolator) compileMessage(text string) *compiledMessage {
+	msg := &compiledMessage{}
+	last := 0
+	for i := 0; i < len(text); {
+		start := strings.Index(text[i:], "%{")
+		if start < 0 {
+			break
+		}
+		start += i
+		end := indexOutsideQuotes(text, start+2, '}', 0)
+		var interpol interpolation
+		ok := end >= 0
+		if ok {
+			interpol, ok = fi.parseDeclaration(text[start+2 : end])
+		}
+		if !ok {
+			end = strings.IndexByte(text[start+2:], '}')
+			if end < 0 {
+				break
+			}
+			end += start + 2
+			interpol, ok = parseLegacyDeclaration(text[start+2 : end])
+		}
+		if !ok {
+			i = start + 2
+			continue
+		}
+		if start > last {
+			msg.segments = append(msg.segments, interpolation{literal: text[last:start]})
+		}
+		interpol.literal = text[start : end+1]
+		msg.segments = append(msg.segments, interpol)
+		msg.interpolations++
+		last = end + 1
+		i = last
+	}
+	if last < len(text) {
+		msg.segments = append(msg.segments, interpolation{literal: text[last:]})
+	}
+	return msg
+}
+
+// parseDeclaration parses the body of a declaration using filters, returning
+// false if it is not one.
+func (fi *filterInterpolator) parseDeclaration(body string) (interpolation, bool) {
+	parts := splitOutsideQuotes(body, '|', 0)
+	interpol := interpolation{placeholder: true, property: strings.TrimSpace(parts[0])}
+	if interpol.property == "" || len(parts) < 2 {
+		return interpol, false
+	}
+	for _, part := range parts[1:] {
+		call, ok := parseFilterCall(part)
+		if ok {
+			call.filter, ok = fi.filters[call.name]
+		}
+		if !ok {
+			return interpol, false
+		}
+		interpol.filters = append(interpol.filters, call)
+	}
+	interpol.hasDefaultValue = true
+	interpol.defaultValue = body[len(parts[0])+1:]
+	return interpol, true
+}
+
+// parseLegacyDeclaration parses the body of a declaration in the format of
+// the Interpolator returned by NewInterpolator, returning false if it is not
+// one.
+func parseLegacyDeclaration(body string) (interpolation, bool) {
+	interpol := interpolation{placeholder: true, property: body}
+	if i := strings.IndexByte(body, '|'); i >= 0 {
+		interpol.property = body[:i]
+		interpol.hasDefaultValue = true
+		interpol.defaultValue = body[i+1:]
+	}
+	return interpol, interpol.property != ""
+}
+
+func (i *interpolation) hasDefaultFilter() bool {
+	for _, call := range i.filters {
+		if call.name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFilterCall(text string) (filterCall, bool) {
+	text = strings.TrimSpace(text)
+	name := text
+	var args []string
+	if i := strings.IndexByte(text, ':'); i >= 0 {
+		name = text[:i]
+		for _, arg := range splitOutsideQuotes(text[i+1:], ',', ':') {
+			arg = strings.TrimSpace(arg)
+			if unquoted, err := strconv.Unquote(arg); err == nil && strings.HasPrefix(arg, `"`) {
+				arg = unquoted
+			}
+			args = append(args, arg)
+		}
+	}
+	if name == "" {
+		return filterCall{}, false
+	}
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return filterCall{}, false
+		}
+	}
+	return filterCall{name: name, args: args}, true
+}
+
+// indexOutsideQuotes returns the index of the first occurrence of c in text
+// from the given offset that is not inside a quoted argument, or -1. Quoted
+// arguments start with a double quote right after a ':' or ',' (ignoring
+// white space) and end with the next unescaped double quote. The prev
+// parameter is taken as the character preceding the offset.
+func indexOutsideQuotes(text string, offset int, c byte, prev byte) int {
+	quoted := false
+	for i := offset; i < len(text); i++ {
+		if quoted {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				quoted = false
+				prev = '"'
+			}
+			continue
+		}
+		switch {
+		case text[i] == '"' && (prev == ':' || prev == ','):
+			quoted = true
+		case text[i] == c:
+			return i
+		case text[i] != ' ' && text[i] != '\t':
+			prev = text[i]
+		}
+	}
+	return -1
+}
+
+func splitOutsideQuotes(text string, sep byte, prev byte) []string {
+	var parts []string
+	for {
+		i := indexOutsideQuotes(text, 0, sep, prev)
+		if i < 0 {
+			return append(parts, text)
+		}
+		parts = append(parts, text[:i])
+		text = text[i+1:]
+		prev = sep
+	}
+}
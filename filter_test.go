@@ -0,0 +1,106 @@
+package katolomb_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestNewFilterInterpolator(t *testing.T) {
+	props := katolomb.NewTranslationProperties(map[string]string{
+		"name":           "alan ginsberg",
+		"title":          "Howl and Other Poems, by Allen Ginsberg",
+		"favorite music": "jazz",
+		"empty":          "",
+	})
+	filters := katolomb.DefaultFilters()
+	filters["reverse"] = func(v string, args ...string) (string, error) {
+		runes := []rune(v)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	}
+	filters["fail"] = func(v string, args ...string) (string, error) {
+		return "", fmt.Errorf("failing filter")
+	}
+	testCases := []struct {
+		text        string
+		result      string
+		errNotNil   bool
+		description string
+	}{
+		{"my name is %{name}", "my name is alan ginsberg", false, "no filters"},
+		{"my name is %{name|upcase}", "my name is ALAN GINSBERG", false, "a filter"},
+		{"my name is %{ name | capitalize }", "my name is Alan ginsberg", false, "a filter surrounded by white space"},
+		{"%{title | truncate:4}", "Howl...", false, "a filter with an argument"},
+		{`%{title | truncate:9,"…" | upcase}`, "HOWL AND …", false, "chained filters with a quoted argument"},
+		{`%{title | truncate:4, " | }"}`, "Howl | }", false, "a quoted argument with a pipe and a closing brace"},
+		{`%{bio | default:"n/a"}`, "n/a", false, "the default filter on a missing property"},
+		{`%{empty | default:"n/a"}`, "n/a", false, "the default filter on an empty property"},
+		{`%{bio | default:"n/a" | upcase}`, "N/A", false, "the default filter chained with another filter"},
+		{"%{name|reverse}", "grebsnig nala", false, "a custom filter"},
+		{"my name is %{firstname|Frida}", "my name is Frida", false, "a legacy default value"},
+		{"my name is %{firstname|}", "my name is ", false, "a legacy empty default value"},
+		{"my name is %{firstname|Frida|Kahlo}", "my name is Frida|Kahlo", false, "a legacy default value with a pipe"},
+		{"I like %{favorite music} music", "I like jazz music", false, "a legacy property with white space"},
+		{"this text %{}", "this text %{}", false, "an empty declaration"},
+		{"my name is %{firstname}", "", true, "a missing property"},
+		{"my name is %{firstname|upcase}", "my name is upcase", false, "a missing property with a filter"},
+		{"my name is %{name|upcase|unknown}", "my name is alan ginsberg", false, "an unknown filter"},
+		{"my name is %{ name }", "", true, "a legacy property with surrounding white space"},
+		{`my name is %{name|x:"} and I like %{favorite music}`, `my name is alan ginsberg and I like jazz`, false, "an unterminated quoted argument"},
+		{"my name is %{name | fail}", "", true, "a failing filter"},
+		{"%{title | truncate}", "", true, "a filter with missing arguments"},
+	}
+	i := katolomb.NewFilterInterpolator(filters)
+	delete(filters, "reverse")
+	for _, tc := range testCases {
+		result, err := i.Interpolate(tc.text, props)
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			t.Errorf("expected Interpolate to return error (%v) for a text with %v, got %v", tc.errNotNil, tc.description, err)
+		}
+		var ie *katolomb.InterpolationError
+		if errNotNil && !errors.As(err, &ie) {
+			t.Errorf("expected Interpolate to return an *InterpolationError for a text with %v, got %v", tc.description, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Interpolate to return %v for a text with %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestNewFilterInterpolatorCompatibility(t *testing.T) {
+	profile := katolomb.NewTranslationProperties(map[string]string{
+		"name":           "Alan Ginsberg",
+		"hobby":          "writing poems",
+		"favorite music": "jazz",
+	})
+	texts := []string{
+		"",
+		"this text %{}",
+		"my name is %{name|Frida Kahlo} and I like %{hobby|feminist activism}",
+		"my name is %{lastname|Ginsberg}, %{name}. My hobby is %{hobby|watching TV} and I like %{favorite music} music",
+		"my name is %{lastname}, %{name}",
+		"unterminated %{name",
+		"my name is %{ name }",
+		"my name is %{lastname|trim}",
+		"my name is %{name|upcase|Mister}",
+		"my name is %{lastname|upcase|Mister}",
+		`my name is %{name|x:"} and I like %{hobby}`,
+		`my name is %{lastname|Note: "hi} there"}`,
+	}
+	legacy := katolomb.NewInterpolator()
+	filtered := katolomb.NewFilterInterpolator(katolomb.DefaultFilters())
+	for _, text := range texts {
+		expected, expectedErr := legacy.Interpolate(text, profile)
+		result, err := filtered.Interpolate(text, profile)
+		if result != expected || (err != nil) != (expectedErr != nil) {
+			t.Errorf("expected Interpolate to return %v, %v for %v, got %v, %v", strconv.Quote(expected), expectedErr, strconv.Quote(text), strconv.Quote(result), err)
+		}
+	}
+}
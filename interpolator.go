@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Interpolator is the interface that wraps the basic interpolate method.
@@ -24,17 +25,35 @@ type Interpolator interface {
 type InterpolatorFunc func(string, TranslationProperties) (string, error)
 
 type interpolator struct {
-	regexp *regexp.Regexp
+	regexp       *regexp.Regexp
+	nameIndex    int
+	defaultIndex int
+	cache        *messageCache
+}
+
+// compiledMessage is the representation of a text parsed by an interpolator:
+// a sequence of segments that are either literal text or interpolations.
+type compiledMessage struct {
+	segments       []interpolation
+	interpolations int
 }
 
 type interpolation struct {
 	literal         string
+	placeholder     bool
 	property        string
 	hasDefaultValue bool
 	defaultValue    string
+	filters         []filterCall
 }
 
-var defaultVanillaInterpolatorRegexp = regexp.MustCompile(`%\{(?P<name>[^\}\|]+)(?P<default>\|[^\}]*)?\}`)
+// DefaultInterpolatorCacheSize is the number of compiled messages kept by the
+// Interpolator returned by NewInterpolator.
+const DefaultInterpolatorCacheSize = 1024
+
+var defaultVanillaInterpolatorRegexp = regexp.MustCompile(`%\{(?P<name>[^\}\|]+)(?:\|(?P<default>[^\}]*))?\}`)
+
+var defaultDeclarationParser = newRegexpInterpolator(defaultVanillaInterpolatorRegexp, 0)
 
 // NewNoErrorInterpolator returns an Interpolator that wraps another
 // Interpolator passed as parameter to avoid returning errors. When the wrapped
@@ -58,8 +77,93 @@ func NewNoErrorInterpolator(i Interpolator) Interpolator {
 //   * <default value> is the value to interpolate when the property is not
 //   available on the TranslationProperties
 //   * the |<default value> part  is optional
+//
+// Texts are parsed once into a compiled representation that is kept in a
+// least-recently-used cache holding up to DefaultInterpolatorCacheSize texts.
 func NewInterpolator() Interpolator {
-	return &interpolator{defaultVanillaInterpolatorRegexp}
+	return NewInterpolatorWithCacheSize(DefaultInterpolatorCacheSize)
+}
+
+// NewInterpolatorWithCacheSize returns an Interpolator like the one returned
+// by NewInterpolator that keeps up to size compiled texts in its
+// least-recently-used cache. A size of 0 or less disables the cache, so that
+// texts are parsed on every interpolation.
+func NewInterpolatorWithCacheSize(size int) Interpolator {
+	return newRegexpInterpolator(defaultVanillaInterpolatorRegexp, size)
+}
+
+// NewInterpolatorWithDelimiters returns an Interpolator like the one returned
+// by NewInterpolator that detects interpolation declarations with the
+// following format:
+//   <opening><property name><separator><default value><closing>
+// where:
+//   * <opening> and <closing> are the given opening and closing delimiters,
+//   such as "{{" and "}}".
+//   * <property name> is the name of the property to interpolate, which cannot
+//   contain the first character of the closing delimiter or the separator.
+//   * <default value> is the value to interpolate when the property is not
+//   available on the TranslationProperties, which cannot contain the first
+//   character of the closing delimiter.
+//   * the <separator><default value> part is optional, and not available when
+//   the separator is empty.
+//
+// An error is returned when any of the delimiters is empty or when the
+// separator starts with the same character as the closing delimiter.
+func NewInterpolatorWithDelimiters(opening, closing, separator string) (Interpolator, error) {
+	if opening == "" {
+		return nil, fmt.Errorf("building interpolator: empty opening delimiter")
+	}
+	if closing == "" {
+		return nil, fmt.Errorf("building interpolator: empty closing delimiter")
+	}
+	closingStart, _ := utf8.DecodeRuneInString(closing)
+	nameExcluded := regexp.QuoteMeta(string(closingStart))
+	pattern := regexp.QuoteMeta(opening) + `(?P<name>[^` + nameExcluded
+	if separator != "" {
+		separatorStart, _ := utf8.DecodeRuneInString(separator)
+		if separatorStart == closingStart {
+			return nil, fmt.Errorf("building interpolator: separator %v starts like closing delimiter %v", strconv.Quote(separator), strconv.Quote(closing))
+		}
+		pattern += regexp.QuoteMeta(string(separatorStart)) + `]+)(?:` + regexp.QuoteMeta(separator) + `(?P<default>[^` + nameExcluded + `]*))?`
+	} else {
+		pattern += `]+)`
+	}
+	pattern += regexp.QuoteMeta(closing)
+	return NewInterpolatorWithRegexp(pattern)
+}
+
+// NewInterpolatorWithRegexp returns an Interpolator like the one returned by
+// NewInterpolator that detects interpolation declarations with the given
+// regular expression. The regular expression must have a group named "name"
+// matching the name of the property to interpolate, and may have a group
+// named "default" matching the default value to interpolate when the property
+// is not available on the TranslationProperties. For instance, the expression
+//   %(?P<name>\d+)\$s
+// detects positional declarations such as %1$s as the property "1".
+//
+// An error is returned when the regular expression is not valid, lacks the
+// "name" group or matches an empty text.
+func NewInterpolatorWithRegexp(pattern string) (Interpolator, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("building interpolator: %v", err)
+	}
+	if re.SubexpIndex("name") < 0 {
+		return nil, fmt.Errorf("building interpolator: regular expression %v has no group named \"name\"", strconv.Quote(pattern))
+	}
+	if re.MatchString("") {
+		return nil, fmt.Errorf("building interpolator: regular expression %v matches an empty text", strconv.Quote(pattern))
+	}
+	return newRegexpInterpolator(re, DefaultInterpolatorCacheSize), nil
+}
+
+func newRegexpInterpolator(re *regexp.Regexp, cacheSize int) *interpolator {
+	return &interpolator{
+		regexp:       re,
+		nameIndex:    re.SubexpIndex("name"),
+		defaultIndex: re.SubexpIndex("default"),
+		cache:        newMessageCache(cacheSize),
+	}
 }
 
 // Interpolate takes a string and a TranslationProperties and returns the string
@@ -67,7 +171,8 @@ func NewInterpolator() Interpolator {
 // TranslationProperties parameter or a default value in the interpolation
 // declaration if available. If a default value is not provided and the property
 // to interpolate is not available on the TranslationProperties parameter, an
-// error will be returned.
+// *InterpolationError wrapping the error returned by the TranslationProperties
+// will be returned.
 //
 // The interpolation in-text declaration format is
 //  %{<property name>|<default value>}
@@ -77,17 +182,27 @@ func NewInterpolator() Interpolator {
 //   available on the TranslationProperties
 //   * the |<default value> part  is optional
 func (i *interpolator) Interpolate(text string, properties TranslationProperties) (string, error) {
-	for _, interpol := range i.findInterpolations(text) {
-		value, err := properties.Property(interpol.property)
+	msg := i.compile(text)
+	if msg.interpolations == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, segment := range msg.segments {
+		if !segment.placeholder {
+			b.WriteString(segment.literal)
+			continue
+		}
+		value, err := properties.Property(segment.property)
 		if err != nil {
-			if !interpol.hasDefaultValue {
-				return "", fmt.Errorf("interpolating %v: %v", strconv.Quote(text), err)
+			if !segment.hasDefaultValue {
+				return "", &InterpolationError{Property: segment.property, Text: text, Err: err}
 			}
-			value = interpol.defaultValue
+			value = segment.defaultValue
 		}
-		text = strings.Replace(text, interpol.literal, value, -1)
+		b.WriteString(value)
 	}
-	return text, nil
+	return b.String(), nil
 }
 
 // Interpolate calls the function with the received text and properties
@@ -96,21 +211,72 @@ func (inF InterpolatorFunc) Interpolate(text string, properties TranslationPrope
 	return inF(text, properties)
 }
 
-func (i *interpolator) findInterpolations(text string) []*interpolation {
-	interpolations := []*interpolation{}
-	for _, interpolationSlice := range i.regexp.FindAllStringSubmatch(text, -1) {
-		var defaultValue string
-		hasDefaultValue := len(interpolationSlice) > 2 && len(interpolationSlice[2]) > 0
-		if hasDefaultValue {
-			defaultValue = interpolationSlice[2][1:]
+func (i *interpolator) compile(text string) *compiledMessage {
+	if msg, ok := i.cache.get(text); ok {
+		return msg
+	}
+	msg := i.compileMessage(text)
+	i.cache.add(text, msg)
+	return msg
+}
+
+func (i *interpolator) compileMessage(text string) *compiledMessage {
+	msg := &compiledMessage{}
+	last := 0
+	for _, m := range i.regexp.FindAllStringSubmatchIndex(text, -1) {
+		if m[2*i.nameIndex] < 0 {
+			continue
 		}
-		interpol := &interpolation{
-			literal:         interpolationSlice[0],
-			property:        interpolationSlice[1],
-			hasDefaultValue: hasDefaultValue,
-			defaultValue:    defaultValue,
+		if m[0] > last {
+			msg.segments = append(msg.segments, interpolation{literal: text[last:m[0]]})
+		}
+		interpol := interpolation{
+			literal:     text[m[0]:m[1]],
+			placeholder: true,
+			property:    text[m[2*i.nameIndex]:m[2*i.nameIndex+1]],
+		}
+		if i.defaultIndex > 0 && m[2*i.defaultIndex] >= 0 {
+			interpol.hasDefaultValue = true
+			interpol.defaultValue = text[m[2*i.defaultIndex]:m[2*i.defaultIndex+1]]
+		}
+		msg.segments = append(msg.segments, interpol)
+		msg.interpolations++
+		last = m[1]
+	}
+	if last < len(text) {
+		msg.segments = append(msg.segments, interpolation{literal: text[last:]})
+	}
+	return msg
+}
+
+// Declaration describes an interpolation declaration found in a text.
+//
+// Text is the declaration as found in the text, Property the name of the
+// property it interpolates and DefaultValue the value interpolated when the
+// property is not available, if HasDefaultValue is true.
+type Declaration struct {
+	Text            string
+	Property        string
+	HasDefaultValue bool
+	DefaultValue    string
+}
+
+// ParseDeclarations returns the interpolation declarations found in the text
+// parameter by the Interpolator returned by NewInterpolator, in the order they
+// appear, so that tools can discover the properties a text requires.
+func ParseDeclarations(text string) []Declaration {
+	msg := defaultDeclarationParser.compileMessage(text)
+	declarations := make([]Declaration, 0, msg.interpolations)
+	for _, segment := range msg.segments {
+		if !segment.placeholder {
+			continue
 		}
-		interpolations = append(interpolations, interpol)
+		declarations = append(declarations, Declaration{
+			Text:            segment.literal,
+			Property:        segment.property,
+			HasDefaultValue: segment.hasDefaultValue,
+			DefaultValue:    segment.defaultValue,
+		})
 	}
-	return interpolations
+	return declarations
 }
@@ -2,7 +2,10 @@ package katolomb_test
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/pbanos/katolomb"
@@ -82,3 +85,206 @@ func TestNewInterpolator(t *testing.T) {
 		}
 	}
 }
+
+func TestNewInterpolatorWithCacheSize(t *testing.T) {
+	props := katolomb.NewTranslationProperties(map[string]string{
+		"name":  "%{hobby}",
+		"hobby": "writing poems",
+	})
+	testCases := []struct {
+		text   string
+		result string
+	}{
+		{"%{name} and %{name}", "%{hobby} and %{hobby}"},
+		{"%{name|}%{missing|} likes %{hobby}", "%{hobby} likes writing poems"},
+		{"%{missing|}", ""},
+		{"no interpolations", "no interpolations"},
+	}
+	for _, size := range []int{0, 1, katolomb.DefaultInterpolatorCacheSize} {
+		i := katolomb.NewInterpolatorWithCacheSize(size)
+		for n := 0; n < 2; n++ {
+			for _, tc := range testCases {
+				result, err := i.Interpolate(tc.text, props)
+				if err != nil {
+					t.Errorf("expected Interpolate not to return error for %v with cache size %d, got %v", strconv.Quote(tc.text), size, err)
+				}
+				if result != tc.result {
+					t.Errorf("expected Interpolate to return %v for %v with cache size %d, got %v", strconv.Quote(tc.result), strconv.Quote(tc.text), size, strconv.Quote(result))
+				}
+			}
+		}
+	}
+}
+
+var benchmarkTexts = []string{
+	"Welcome back!",
+	"how are you %{timestamp}, %{name|Mister}?",
+	"%{count} new messages from %{sender} in %{folder|Inbox}, last one at %{timestamp}",
+}
+
+var benchmarkProperties = katolomb.NewTranslationProperties(map[string]string{
+	"timestamp": "today",
+	"name":      "Mr. Darcy",
+	"count":     "3",
+	"sender":    "Elizabeth",
+})
+
+var benchmarkRegexp = regexp.MustCompile(`%\{(?P<name>[^\}\|]+)(?P<default>\|[^\}]*)?\}`)
+
+// legacyInterpolate reproduces the interpolation path used before compiled
+// messages, as a baseline for the benchmarks.
+func legacyInterpolate(text string, properties katolomb.TranslationProperties) (string, error) {
+	for _, m := range benchmarkRegexp.FindAllStringSubmatch(text, -1) {
+		value, err := properties.Property(m[1])
+		if err != nil {
+			if len(m[2]) == 0 {
+				return "", err
+			}
+			value = m[2][1:]
+		}
+		text = strings.Replace(text, m[0], value, -1)
+	}
+	return text, nil
+}
+
+func benchmarkInterpolator(b *testing.B, i katolomb.Interpolator) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		for _, text := range benchmarkTexts {
+			if _, err := i.Interpolate(text, benchmarkProperties); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkInterpolatorLegacy(b *testing.B) {
+	benchmarkInterpolator(b, katolomb.InterpolatorFunc(legacyInterpolate))
+}
+
+func BenchmarkInterpolatorUncached(b *testing.B) {
+	benchmarkInterpolator(b, katolomb.NewInterpolatorWithCacheSize(0))
+}
+
+func BenchmarkInterpolatorCached(b *testing.B) {
+	benchmarkInterpolator(b, katolomb.NewInterpolator())
+}
+
+func BenchmarkInterpolatorCachedParallel(b *testing.B) {
+	i := katolomb.NewInterpolator()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for _, text := range benchmarkTexts {
+				if _, err := i.Interpolate(text, benchmarkProperties); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestNewInterpolatorWithDelimiters(t *testing.T) {
+	profile := katolomb.NewTranslationProperties(map[string]string{
+		"name":  "Alan Ginsberg",
+		"hobby": "writing poems",
+	})
+	testCases := []struct {
+		opening, closing, separator string
+		text                        string
+		result                      string
+		errNotNil                   bool
+		description                 string
+	}{
+		{"{{", "}}", "|", "my name is {{name}} and I like {{hobby}}", "my name is Alan Ginsberg and I like writing poems", false, "double-brace delimiters"},
+		{"{{", "}}", "|", "my name is {{firstname|Frida}}", "my name is Frida", false, "double-brace delimiters with a default value"},
+		{"{{", "}}", "|", "my name is %{name}", "my name is %{name}", false, "double-brace delimiters and a text with other delimiters"},
+		{"{{", "}}", "|", "my name is {{firstname}}", "", true, "double-brace delimiters without property available"},
+		{"${", "}", ":-", "my name is ${firstname:-Frida}, ${name:-Frida}", "my name is Frida, Alan Ginsberg", false, "shell-like delimiters and separator"},
+		{"[", "]", "", "my name is [name]", "my name is Alan Ginsberg", false, "single-character delimiters without separator"},
+		{"<", ">", "", "my name is <firstname|Frida>", "", true, "delimiters without separator and a default value declared"},
+	}
+	for _, tc := range testCases {
+		i, err := katolomb.NewInterpolatorWithDelimiters(tc.opening, tc.closing, tc.separator)
+		if err != nil {
+			t.Fatalf("expected NewInterpolatorWithDelimiters not to return error with %v, got %v", tc.description, err)
+		}
+		result, err := i.Interpolate(tc.text, profile)
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			t.Errorf("expected Interpolate to return error (%v) for a text with %v, got %v", tc.errNotNil, tc.description, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Interpolate to return %v for a text with %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+	invalid := []struct {
+		opening, closing, separator string
+		description                 string
+	}{
+		{"", "}}", "|", "an empty opening delimiter"},
+		{"{{", "", "|", "an empty closing delimiter"},
+		{"{", "}", "}}", "a separator starting like the closing delimiter"},
+	}
+	for _, tc := range invalid {
+		if _, err := katolomb.NewInterpolatorWithDelimiters(tc.opening, tc.closing, tc.separator); err == nil {
+			t.Errorf("expected NewInterpolatorWithDelimiters to return error with %v", tc.description)
+		}
+	}
+}
+
+func TestNewInterpolatorWithRegexp(t *testing.T) {
+	props := katolomb.NewTranslationProperties(map[string]string{"1": "Alan", "2": "poems"})
+	i, err := katolomb.NewInterpolatorWithRegexp(`%(?P<name>\d+)\$s`)
+	if err != nil {
+		t.Fatalf("expected NewInterpolatorWithRegexp not to return error, got %v", err)
+	}
+	result, err := i.Interpolate("%1$s writes %2$s", props)
+	if err != nil || result != "Alan writes poems" {
+		t.Errorf("expected Interpolate to return %q, got %q, %v", "Alan writes poems", result, err)
+	}
+	i, err = katolomb.NewInterpolatorWithRegexp(`<(?P<name>\w+)(?:=(?P<default>\w*))?>`)
+	if err != nil {
+		t.Fatalf("expected NewInterpolatorWithRegexp not to return error, got %v", err)
+	}
+	result, err = i.Interpolate("<1> writes <3=songs><4=>", props)
+	if err != nil || result != "Alan writes songs" {
+		t.Errorf("expected Interpolate to return %q, got %q, %v", "Alan writes songs", result, err)
+	}
+	invalid := []struct {
+		pattern     string
+		description string
+	}{
+		{`%(?P<name>\d+`, "an invalid regular expression"},
+		{`%(\d+)\$s`, "a regular expression without a name group"},
+		{`(?P<name>\d*)`, "a regular expression matching an empty text"},
+	}
+	for _, tc := range invalid {
+		if _, err := katolomb.NewInterpolatorWithRegexp(tc.pattern); err == nil {
+			t.Errorf("expected NewInterpolatorWithRegexp to return error with %v", tc.description)
+		}
+	}
+}
+
+func TestParseDeclarations(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []katolomb.Declaration
+	}{
+		{"No declarations", []katolomb.Declaration{}},
+		{"Sent at %{timestamp} by %{name|someone}", []katolomb.Declaration{
+			{Text: "%{timestamp}", Property: "timestamp"},
+			{Text: "%{name|someone}", Property: "name", HasDefaultValue: true, DefaultValue: "someone"},
+		}},
+		{"%{name|} and %{name}, unterminated %{other", []katolomb.Declaration{
+			{Text: "%{name|}", Property: "name", HasDefaultValue: true},
+			{Text: "%{name}", Property: "name"},
+		}},
+	}
+	for _, tc := range testCases {
+		result := katolomb.ParseDeclarations(tc.text)
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("expected ParseDeclarations to return %v for %q, got %v", tc.expected, tc.text, result)
+		}
+	}
+}
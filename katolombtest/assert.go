@@ -0,0 +1,64 @@
+package katolombtest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+// AssertTranslated translates the key with the Translator and the given
+// properties, and reports an error to t unless the translation succeeds and
+// returns the expected text. It returns whether the assertion passed.
+func AssertTranslated(t testing.TB, translator katolomb.Translator, key string, props map[string]string, expected string) bool {
+	t.Helper()
+	result, err := translator.Translate(key, katolomb.NewTranslationProperties(props))
+	if err != nil {
+		t.Errorf("expected %v to translate to %v, got error %v", strconv.Quote(key), strconv.Quote(expected), err)
+		return false
+	}
+	if result != expected {
+		t.Errorf("expected %v to translate to %v, got %v", strconv.Quote(key), strconv.Quote(expected), strconv.Quote(result))
+		return false
+	}
+	return true
+}
+
+// AssertAllKeysPresent takes a map of locales to EnumerableTranslators and
+// reports an error to t for each locale lacking keys available in any of the
+// other locales, listing the missing keys. It returns whether the assertion
+// passed.
+func AssertAllKeysPresent(t testing.TB, translators map[string]katolomb.EnumerableTranslator) bool {
+	t.Helper()
+	locales := make([]string, 0, len(translators))
+	for locale := range translators {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	passed := true
+	for _, locale := range locales {
+		missing := make(map[string]bool)
+		for _, other := range locales {
+			if other == locale {
+				continue
+			}
+			_, onlyOther := katolomb.DiffKeys(translators[locale], translators[other])
+			for _, key := range onlyOther {
+				missing[key] = true
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(missing))
+		for key := range missing {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		t.Errorf("expected locale %v to have all keys, missing %v", locale, strings.Join(keys, ", "))
+		passed = false
+	}
+	return passed
+}
@@ -0,0 +1,67 @@
+package katolombtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+	"github.com/pbanos/katolomb/katolombtest"
+)
+
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertTranslated(t *testing.T) {
+	ft := katolombtest.NewTranslator(map[string]string{"greeting": "Hello %{name}"})
+	testCases := []struct {
+		key         string
+		props       map[string]string
+		expected    string
+		passed      bool
+		description string
+	}{
+		{"greeting", map[string]string{"name": "John"}, "Hello John", true, "the translation matches"},
+		{"greeting", map[string]string{"name": "Ana"}, "Hello John", false, "the translation does not match"},
+		{"greeting", nil, "Hello John", false, "the translation fails"},
+		{"missing", nil, "", false, "the key is missing"},
+	}
+	for _, tc := range testCases {
+		tb := &fakeTB{}
+		passed := katolombtest.AssertTranslated(tb, ft, tc.key, tc.props, tc.expected)
+		if passed != tc.passed || (len(tb.errors) == 0) != tc.passed {
+			t.Errorf("expected AssertTranslated to return %v and report %v errors when %v, got %v and %v", tc.passed, !tc.passed, tc.description, passed, tb.errors)
+		}
+	}
+}
+
+func TestAssertAllKeysPresent(t *testing.T) {
+	en := katolombtest.NewTranslator(map[string]string{"hello": "Hello", "bye": "Bye", "thanks": "Thanks"})
+	es := katolombtest.NewTranslator(map[string]string{"hello": "Hola", "bye": "Adiós"})
+	fr := katolombtest.NewTranslator(map[string]string{"hello": "Bonjour", "welcome": "Bienvenue"})
+	tb := &fakeTB{}
+	if katolombtest.AssertAllKeysPresent(tb, map[string]katolomb.EnumerableTranslator{"en": en, "es": es}) || len(tb.errors) != 1 {
+		t.Errorf("expected AssertAllKeysPresent to fail with one error when a locale lacks keys, got %v", tb.errors)
+	}
+	tb = &fakeTB{}
+	katolombtest.AssertAllKeysPresent(tb, map[string]katolomb.EnumerableTranslator{"en": en, "es": es, "fr": fr})
+	expected := []string{
+		"expected locale en to have all keys, missing welcome",
+		"expected locale es to have all keys, missing thanks, welcome",
+		"expected locale fr to have all keys, missing bye, thanks",
+	}
+	if fmt.Sprint(tb.errors) != fmt.Sprint(expected) {
+		t.Errorf("expected AssertAllKeysPresent to report %v, got %v", expected, tb.errors)
+	}
+	tb = &fakeTB{}
+	if !katolombtest.AssertAllKeysPresent(tb, map[string]katolomb.EnumerableTranslator{"en": en, "en-GB": en}) || len(tb.errors) != 0 {
+		t.Errorf("expected AssertAllKeysPresent to pass when locales have the same keys, got %v", tb.errors)
+	}
+}
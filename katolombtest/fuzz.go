@@ -0,0 +1,60 @@
+//go:build go1.18
+
+package katolombtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+var fuzzSeeds = []string{
+	"",
+	"no declarations",
+	"Hello %{name}!",
+	"Hello %{name|stranger}, %{name}",
+	"%{name|}%{}%{|default}",
+	"unterminated %{name",
+	"%{{name}}",
+	"%{name|upcase|truncate:3,\"…\"}",
+	"100% sure, %s and %1$s and %%",
+	"%{ñ|ü}\u202e%{name}",
+}
+
+// FuzzInterpolator registers a seed corpus of texts in f and fuzzes the
+// Interpolator parameter with it, failing when interpolating a text:
+//   * panics
+//   * returns different results in consecutive calls
+//   * returns a different result once wrapped with
+//   katolomb.NewNoErrorInterpolator, when it does not return an error
+//
+// It is meant to be called from a fuzz test, such as:
+//   func FuzzMyInterpolator(f *testing.F) {
+//     katolombtest.FuzzInterpolator(f, NewMyInterpolator())
+//   }
+func FuzzInterpolator(f *testing.F, interpolator katolomb.Interpolator) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed, "value")
+	}
+	noError := katolomb.NewNoErrorInterpolator(interpolator)
+	f.Fuzz(func(t *testing.T, text, value string) {
+		props := katolomb.TranslationPropertiesFunc(func(name string) (string, error) {
+			if strings.HasPrefix(name, "missing") {
+				return "", katolomb.ErrPropertyMissing
+			}
+			return value, nil
+		})
+		first, firstErr := interpolator.Interpolate(text, props)
+		second, secondErr := interpolator.Interpolate(text, props)
+		if first != second || (firstErr == nil) != (secondErr == nil) {
+			t.Errorf("expected interpolating %q twice to return the same result, got %q and %v then %q and %v", text, first, firstErr, second, secondErr)
+		}
+		if firstErr != nil {
+			return
+		}
+		if result, _ := noError.Interpolate(text, props); result != first {
+			t.Errorf("expected interpolating %q with a no error interpolator to return %q, got %q", text, first, result)
+		}
+	})
+}
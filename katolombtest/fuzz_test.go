@@ -0,0 +1,30 @@
+//go:build go1.18
+
+package katolombtest_test
+
+import (
+	"testing"
+
+	"github.com/pbanos/katolomb"
+	"github.com/pbanos/katolomb/katolombtest"
+)
+
+func FuzzNewInterpolator(f *testing.F) {
+	katolombtest.FuzzInterpolator(f, katolomb.NewInterpolator())
+}
+
+func FuzzNewFilterInterpolator(f *testing.F) {
+	katolombtest.FuzzInterpolator(f, katolomb.NewFilterInterpolator(katolomb.DefaultFilters()))
+}
+
+func FuzzNewPrintfInterpolator(f *testing.F) {
+	katolombtest.FuzzInterpolator(f, katolomb.NewPrintfInterpolator())
+}
+
+func FuzzNewInterpolatorWithDelimiters(f *testing.F) {
+	interpolator, err := katolomb.NewInterpolatorWithDelimiters("{{", "}}", "|")
+	if err != nil {
+		f.Fatal(err)
+	}
+	katolombtest.FuzzInterpolator(f, interpolator)
+}
@@ -0,0 +1,63 @@
+package katolombtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+var update = flag.Bool("katolombtest.update", false, "update the golden files of katolombtest.AssertGolden")
+
+// AssertGolden translates each of the messages with the Translator and
+// compares the results with the golden file at path, reporting an error to t
+// if they differ or the file cannot be read. It returns whether the assertion
+// passed.
+//
+// The golden file holds a JSON object with the names of the messages as
+// attributes and their translations as values, or the message of the error
+// prefixed by "error: " when they cannot be translated. When tests are run
+// with the -katolombtest.update flag, the golden file is written instead.
+func AssertGolden(t testing.TB, translator katolomb.Translator, path string, messages map[string]katolomb.Translatable) bool {
+	t.Helper()
+	rendered := make(map[string]string, len(messages))
+	for name, msg := range messages {
+		result, err := msg.Translate(translator)
+		if err != nil {
+			result = "error: " + err.Error()
+		}
+		rendered[name] = result
+	}
+	actual, err := json.MarshalIndent(rendered, "", "  ")
+	if err != nil {
+		t.Errorf("rendering golden file %v: %v", path, err)
+		return false
+	}
+	actual = append(actual, '\n')
+	if *update {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, actual, 0644)
+		}
+		if err != nil {
+			t.Errorf("updating golden file %v: %v", path, err)
+			return false
+		}
+		return true
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("reading golden file %v: %v (run tests with -katolombtest.update to create it)", path, err)
+		return false
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("expected rendered messages to match golden file %v (run tests with -katolombtest.update to update it), got:\n%s", path, actual)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package katolombtest_test
+
+import (
+	"testing"
+
+	"github.com/pbanos/katolomb"
+	"github.com/pbanos/katolomb/katolombtest"
+)
+
+func TestAssertGolden(t *testing.T) {
+	ft := katolombtest.NewTranslator(map[string]string{
+		"greeting": "Hello %{name}",
+		"inbox":    "You have %{count} messages",
+	})
+	messages := map[string]katolomb.Translatable{
+		"greeting": katolomb.NewMessage("greeting").With("name", "John"),
+		"inbox":    katolomb.NewMessage("inbox").With("count", "3"),
+		"missing":  katolomb.NewMessage("missing"),
+	}
+	katolombtest.AssertGolden(t, ft, "testdata/messages.golden.json", messages)
+
+	tb := &fakeTB{}
+	messages["greeting"] = katolomb.NewMessage("greeting").With("name", "Ana")
+	if katolombtest.AssertGolden(tb, ft, "testdata/messages.golden.json", messages) || len(tb.errors) != 1 {
+		t.Errorf("expected AssertGolden to fail when rendered messages differ from the golden file, got %v", tb.errors)
+	}
+	tb = &fakeTB{}
+	if katolombtest.AssertGolden(tb, ft, "testdata/missing.golden.json", messages) || len(tb.errors) != 1 {
+		t.Errorf("expected AssertGolden to fail when the golden file does not exist, got %v", tb.errors)
+	}
+}
@@ -0,0 +1,111 @@
+// Package katolombtest provides utilities to test code using katolomb
+// translators: a recording fake Translator, assertion helpers, golden files
+// for rendered messages and a fuzz target for interpolators.
+package katolombtest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pbanos/katolomb"
+)
+
+// Call holds the record of a translation by a Translator.
+//
+// Key is the key translated, Properties the values of the properties
+// obtained from the TranslationProperties during the translation, and Err the
+// error returned, if any.
+type Call struct {
+	Key        string
+	Properties map[string]string
+	Err        error
+}
+
+// Translator is a fake katolomb.Translator that translates keys with a map of
+// texts interpolated by the Interpolator returned by katolomb.NewInterpolator,
+// recording every translation. It is safe for concurrent use, as long as the
+// map of texts is not modified.
+type Translator struct {
+	texts        map[string]string
+	interpolator katolomb.Interpolator
+	mu           sync.Mutex
+	calls        []Call
+}
+
+// NewTranslator takes a map of keys to texts and returns a Translator
+// translating them. Keys not in the map produce errors wrapping
+// katolomb.ErrKeyNotFound.
+func NewTranslator(texts map[string]string) *Translator {
+	return &Translator{texts: texts, interpolator: katolomb.NewInterpolator()}
+}
+
+// Translate returns the text for the key interpolated with the properties
+// parameter, which may be nil, recording the key, the values of the
+// properties obtained and the error returned.
+func (ft *Translator) Translate(key string, props katolomb.TranslationProperties) (string, error) {
+	if props == nil {
+		props = katolomb.NewTranslationProperties(nil)
+	}
+	call := Call{Key: key, Properties: make(map[string]string)}
+	defer func() {
+		ft.mu.Lock()
+		ft.calls = append(ft.calls, call)
+		ft.mu.Unlock()
+	}()
+	text, ok := ft.texts[key]
+	if !ok {
+		call.Err = fmt.Errorf("translating %v: %w", strconv.Quote(key), katolomb.ErrKeyNotFound)
+		return "", call.Err
+	}
+	recording := katolomb.TranslationPropertiesFunc(func(name string) (string, error) {
+		value, err := props.Property(name)
+		if err == nil {
+			call.Properties[name] = value
+		}
+		return value, err
+	})
+	var t string
+	t, call.Err = ft.interpolator.Interpolate(text, recording)
+	return t, call.Err
+}
+
+// Calls returns the records of the translations so far, in the order they
+// happened.
+func (ft *Translator) Calls() []Call {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	calls := make([]Call, len(ft.calls))
+	copy(calls, ft.calls)
+	return calls
+}
+
+// Keys returns the sorted list of keys with a text, so that the Translator is
+// also a katolomb.EnumerableTranslator.
+func (ft *Translator) Keys() []string {
+	keys := make([]string, 0, len(ft.texts))
+	for key := range ft.texts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TranslatedKeys returns the keys translated so far, in the order they were
+// translated.
+func (ft *Translator) TranslatedKeys() []string {
+	calls := ft.Calls()
+	keys := make([]string, 0, len(calls))
+	for _, call := range calls {
+		keys = append(keys, call.Key)
+	}
+	return keys
+}
+
+// Reset discards the records of the translations so far.
+func (ft *Translator) Reset() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.calls = nil
+}
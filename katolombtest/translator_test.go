@@ -0,0 +1,53 @@
+package katolombtest_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+	"github.com/pbanos/katolomb/katolombtest"
+)
+
+func TestTranslator(t *testing.T) {
+	ft := katolombtest.NewTranslator(map[string]string{
+		"greeting": "Hello %{name}, %{title|friend}",
+		"bye":      "Bye!",
+	})
+	props := katolomb.NewTranslationProperties(map[string]string{"name": "John", "unused": "x"})
+	if result, err := ft.Translate("greeting", props); result != "Hello John, friend" || err != nil {
+		t.Errorf("expected Translate to interpolate the text of the key, got %q and %v", result, err)
+	}
+	if _, err := ft.Translate("missing", props); !errors.Is(err, katolomb.ErrKeyNotFound) {
+		t.Errorf("expected Translate to return ErrKeyNotFound for keys with no text, got %v", err)
+	}
+	if _, err := ft.Translate("greeting", katolomb.NewTranslationProperties(nil)); !errors.Is(err, katolomb.ErrPropertyMissing) {
+		t.Errorf("expected Translate to return the interpolation error, got %v", err)
+	}
+	calls := ft.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("expected Calls to return 3 records, got %d", len(calls))
+	}
+	expected := []katolombtest.Call{
+		{Key: "greeting", Properties: map[string]string{"name": "John"}},
+		{Key: "missing", Properties: map[string]string{}, Err: calls[1].Err},
+		{Key: "greeting", Properties: map[string]string{}, Err: calls[2].Err},
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected Calls to return %v, got %v", expected, calls)
+	}
+	if keys := ft.TranslatedKeys(); !reflect.DeepEqual(keys, []string{"greeting", "missing", "greeting"}) {
+		t.Errorf("expected TranslatedKeys to return the keys translated in order, got %v", keys)
+	}
+	var et katolomb.EnumerableTranslator = ft
+	if keys := et.Keys(); !reflect.DeepEqual(keys, []string{"bye", "greeting"}) {
+		t.Errorf("expected Keys to return the sorted keys with a text, got %v", keys)
+	}
+	ft.Reset()
+	if calls := ft.Calls(); len(calls) != 0 {
+		t.Errorf("expected Reset to discard the records, got %v", calls)
+	}
+	if result, err := ft.Translate("bye", nil); result != "Bye!" || err != nil {
+		t.Errorf("expected Translate to accept nil properties, got %q and %v", result, err)
+	}
+}
@@ -0,0 +1,36 @@
+package katolomb
+
+import "sort"
+
+// EnumerableTranslator is the interface offered by translators that can list
+// the keys they are able to translate.
+//
+// Keys returns the sorted list of keys with a translation available.
+type EnumerableTranslator interface {
+	Translator
+	Keys() []string
+}
+
+// DiffKeys takes two EnumerableTranslator parameters and returns the sorted
+// lists of keys only available in the first one and only available in the
+// second one.
+func DiffKeys(a, b EnumerableTranslator) ([]string, []string) {
+	aKeys := a.Keys()
+	bKeys := b.Keys()
+	return keysNotIn(aKeys, bKeys), keysNotIn(bKeys, aKeys)
+}
+
+func keysNotIn(keys, others []string) []string {
+	set := make(map[string]bool, len(others))
+	for _, k := range others {
+		set[k] = true
+	}
+	result := []string{}
+	for _, k := range keys {
+		if !set[k] {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,58 @@
+package katolomb_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestYAMLTranslatorKeys(t *testing.T) {
+	yml := []byte(`---
+greetings:
+  hello: Hello!
+  bye:
+    night: Good night!
+numbers:
+- zero
+- one
+empty: ~
+top: Top`)
+	testCases := []struct {
+		separator   string
+		keys        []string
+		description string
+	}{
+		{".", []string{"greetings.bye.night", "greetings.hello", "numbers.0", "numbers.1", "top"}, "using the default separator"},
+		{"/", []string{"greetings/bye/night", "greetings/hello", "numbers/0", "numbers/1", "top"}, "using a custom separator"},
+		{"", []string{"top"}, "using no separator"},
+	}
+	for _, tc := range testCases {
+		translator, err := katolomb.NewYAMLTranslatorWithSeparator(yml, tc.separator)
+		if err != nil {
+			t.Fatalf("expected NewYAMLTranslatorWithSeparator not to return error, got %v", err)
+		}
+		keys := translator.(katolomb.EnumerableTranslator).Keys()
+		if !reflect.DeepEqual(keys, tc.keys) {
+			t.Errorf("expected Keys to return %v when %v, got %v", tc.keys, tc.description, keys)
+		}
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	en, err := katolomb.NewYAMLTranslator([]byte(`{"a": "A", "b": {"c": "C", "d": "D"}}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	es, err := katolomb.NewYAMLTranslator([]byte(`{"a": "A", "b": {"c": "C"}, "e": "E"}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	onlyEn, onlyEs := katolomb.DiffKeys(en.(katolomb.EnumerableTranslator), es.(katolomb.EnumerableTranslator))
+	if !reflect.DeepEqual(onlyEn, []string{"b.d"}) {
+		t.Errorf("expected DiffKeys to return [b.d] as keys only in the first translator, got %v", onlyEn)
+	}
+	if !reflect.DeepEqual(onlyEs, []string{"e"}) {
+		t.Errorf("expected DiffKeys to return [e] as keys only in the second translator, got %v", onlyEs)
+	}
+}
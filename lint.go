@@ -0,0 +1,383 @@
+package katolomb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LintMessage is the translation of a key given to lint rules.
+//
+// Locale is the locale of the translation, Key its key and Text its
+// uninterpolated text. SourceLocale is the locale translations are made from,
+// and Source the text of the key in the source locale, if HasSource is true.
+type LintMessage struct {
+	Locale       string
+	Key          string
+	Text         string
+	SourceLocale string
+	Source       string
+	HasSource    bool
+}
+
+// LintRule is the type of functions that check translations. It takes a
+// LintMessage and returns the descriptions of the problems found in it.
+type LintRule func(LintMessage) []string
+
+// LintRules is a registry of lint rules indexed by their names.
+type LintRules map[string]LintRule
+
+// LintIssue describes a problem found by a lint rule in the translation of a
+// key.
+//
+// Rule is the name of the rule, Locale the locale of the translation, Key its
+// key and Message the description of the problem.
+type LintIssue struct {
+	Rule    string `json:"rule"`
+	Locale  string `json:"locale"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// DefaultLengthRatioMin and DefaultLengthRatioMax are the bounds of the ratio
+// between the length of a translation and its source's used by the
+// "length-ratio" rule returned by DefaultLintRules.
+const (
+	DefaultLengthRatioMin = 0.5
+	DefaultLengthRatioMax = 2.0
+)
+
+var terminalPunctuation = map[rune]rune{
+	'.': '.', '!': '!', '?': '?', ':': ':', ';': ';', '…': '…',
+	'。': '.', '！': '!', '？': '?', '：': ':', '；': ';',
+}
+
+var closingBrackets = map[rune]rune{
+	')': '(', ']': '[', '}': '{', '」': '「',
+}
+
+var openingBrackets = map[rune]bool{
+	'(': true, '[': true, '{': true, '「': true,
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// DefaultLintRules returns a new LintRules registry with the following rules:
+//   * whitespace: translations whose leading or trailing white space differs
+//   from their source's.
+//   * untranslated: translations identical to their source.
+//   * length-ratio: translations whose length is less than
+//   DefaultLengthRatioMin or more than DefaultLengthRatioMax times their
+//   source's (see NewLengthRatioRule).
+//   * punctuation: translations whose terminal punctuation differs from their
+//   source's.
+//   * balance: translations with unbalanced quotes or brackets. Guillemets
+//   are only required to come in pairs, since their direction depends on the
+//   language (e.g. «…» in French and »…« in German), and curly quotes are
+//   not checked, since languages pair them differently (e.g. “…” in English
+//   and „…“ in German).
+//
+// Rules comparing translations with their source skip the translations of
+// the source locale and keys with no source. Other rules, such as the one
+// returned by NewForbiddenTermsRule, can be added to the registry.
+func DefaultLintRules() LintRules {
+	return LintRules{
+		"whitespace":   lintWhitespace,
+		"untranslated": lintUntranslated,
+		"length-ratio": NewLengthRatioRule(DefaultLengthRatioMin, DefaultLengthRatioMax),
+		"punctuation":  lintPunctuation,
+		"balance":      lintBalance,
+	}
+}
+
+// NewLengthRatioRule takes the minimum and maximum ratios between the length
+// of a translation and the length of its source and returns a LintRule
+// reporting translations outside of them. Sources shorter than 10 characters
+// are skipped, since the length of short texts varies too much between
+// languages, and interpolation declarations are not counted.
+func NewLengthRatioRule(min, max float64) LintRule {
+	return func(msg LintMessage) []string {
+		if !msg.comparable() {
+			return nil
+		}
+		sourceLen := utf8.RuneCountInString(stripDeclarations(msg.Source))
+		if sourceLen < 10 {
+			return nil
+		}
+		ratio := float64(utf8.RuneCountInString(stripDeclarations(msg.Text))) / float64(sourceLen)
+		if ratio < min || ratio > max {
+			return []string{fmt.Sprintf("length is %.2f times the source's", ratio)}
+		}
+		return nil
+	}
+}
+
+// NewForbiddenTermsRule takes a map of locales to lists of terms and returns a
+// LintRule reporting translations containing any of the terms of their
+// locale, ignoring case. Terms listed under a language (such as "es") apply
+// to its locales (such as "es-MX" or "es_ES"), and terms listed under "*"
+// apply to all locales.
+func NewForbiddenTermsRule(terms map[string][]string) LintRule {
+	return func(msg LintMessage) []string {
+		var problems []string
+		text := strings.ToLower(msg.Text)
+		candidates := terms[msg.Locale]
+		if language := localeLanguage(msg.Locale); language != msg.Locale {
+			candidates = append(candidates[:len(candidates):len(candidates)], terms[language]...)
+		}
+		candidates = append(candidates[:len(candidates):len(candidates)], terms["*"]...)
+		for _, term := range candidates {
+			if term != "" && strings.Contains(text, strings.ToLower(term)) {
+				problems = append(problems, fmt.Sprintf("contains forbidden term %v", strconv.Quote(term)))
+			}
+		}
+		return problems
+	}
+}
+
+// Lint takes the name of a source locale, a map of locales to
+// EnumerableTranslator, which must include the source locale, and LintRules,
+// and returns the issues found by the rules in the translations of every
+// locale, sorted by key, locale and rule.
+//
+// Translations are obtained with no properties available, so translators
+// must return them uninterpolated.
+func Lint(source string, translators map[string]EnumerableTranslator, rules LintRules) ([]LintIssue, error) {
+	src, ok := translators[source]
+	if !ok {
+		return nil, fmt.Errorf("linting: no translator for source locale %v", source)
+	}
+	sourceTexts := placeholderTexts(src)
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var issues []LintIssue
+	for locale, translator := range translators {
+		texts := sourceTexts
+		if locale != source {
+			texts = placeholderTexts(translator)
+		}
+		for key, text := range texts {
+			msg := LintMessage{Locale: locale, Key: key, Text: text, SourceLocale: source}
+			msg.Source, msg.HasSource = sourceTexts[key]
+			for _, name := range names {
+				for _, problem := range rules[name](msg) {
+					issues = append(issues, LintIssue{Rule: name, Locale: locale, Key: key, Message: problem})
+				}
+			}
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+	return issues, nil
+}
+
+// WriteLintReport writes the issues parameter to w as a JSON array with an
+// object with "rule", "locale", "key" and "message" attributes for each
+// issue.
+func WriteLintReport(w io.Writer, issues []LintIssue) error {
+	if issues == nil {
+		issues = []LintIssue{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(issues)
+	if err != nil {
+		return fmt.Errorf("writing lint report: %v", err)
+	}
+	return nil
+}
+
+// WriteLintSARIF writes the issues parameter to w as a SARIF 2.1.0 log, so
+// that it can be shown by tools supporting static analysis results. Each
+// issue is a warning result for its rule, located in an artifact whose URI is
+// its locale (such as the path of the locale file) at a logical location
+// named after its key.
+func WriteLintSARIF(w io.Writer, issues []LintIssue) error {
+	run := sarifRun{Results: make([]sarifResult, 0, len(issues))}
+	run.Tool.Driver.Name = "katolomb"
+	run.Tool.Driver.InformationURI = "https://github.com/pbanos/katolomb"
+	run.Tool.Driver.Rules = []sarifRule{}
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if !seen[issue.Rule] {
+			seen[issue.Rule] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: issue.Rule})
+		}
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = issue.Locale
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: issue.Key}}
+		result := sarifResult{RuleID: issue.Rule, Level: "warning", Locations: []sarifLocation{loc}}
+		result.Message.Text = issue.Message
+		run.Results = append(run.Results, result)
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+	if err != nil {
+		return fmt.Errorf("writing lint report: %v", err)
+	}
+	return nil
+}
+
+func (msg LintMessage) comparable() bool {
+	return msg.HasSource && msg.Locale != msg.SourceLocale
+}
+
+func lintWhitespace(msg LintMessage) []string {
+	if !msg.comparable() {
+		return nil
+	}
+	var problems []string
+	if hasLeadingSpace(msg.Text) != hasLeadingSpace(msg.Source) {
+		problems = append(problems, "leading white space differs from the source's")
+	}
+	if hasTrailingSpace(msg.Text) != hasTrailingSpace(msg.Source) {
+		problems = append(problems, "trailing white space differs from the source's")
+	}
+	return problems
+}
+
+func lintUntranslated(msg LintMessage) []string {
+	if !msg.comparable() || msg.Text != msg.Source {
+		return nil
+	}
+	if strings.IndexFunc(stripDeclarations(msg.Text), unicode.IsLetter) < 0 {
+		return nil
+	}
+	return []string{"identical to the source"}
+}
+
+func lintPunctuation(msg LintMessage) []string {
+	if !msg.comparable() {
+		return nil
+	}
+	p, sp := terminal(msg.Text), terminal(msg.Source)
+	if p == sp {
+		return nil
+	}
+	return []string{fmt.Sprintf("ends with %v but the source ends with %v", describeTerminal(p), describeTerminal(sp))}
+}
+
+func lintBalance(msg LintMessage) []string {
+	var problems []string
+	text := stripDeclarations(msg.Text)
+	if strings.Count(text, `"`)%2 != 0 {
+		problems = append(problems, `unbalanced quote "\""`)
+	}
+	if strings.Count(text, "«") != strings.Count(text, "»") {
+		problems = append(problems, `unbalanced quotes "«" and "»"`)
+	}
+	var stack []rune
+	for _, r := range text {
+		if openingBrackets[r] {
+			stack = append(stack, r)
+			continue
+		}
+		opening, ok := closingBrackets[r]
+		if !ok {
+			continue
+		}
+		if len(stack) == 0 || stack[len(stack)-1] != opening {
+			problems = append(problems, fmt.Sprintf("unexpected %v", strconv.Quote(string(r))))
+			continue
+		}
+		stack = stack[:len(stack)-1]
+	}
+	for _, r := range stack {
+		problems = append(problems, fmt.Sprintf("unclosed %v", strconv.Quote(string(r))))
+	}
+	return problems
+}
+
+func stripDeclarations(text string) string {
+	return defaultVanillaInterpolatorRegexp.ReplaceAllString(text, "")
+}
+
+func hasLeadingSpace(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return text != "" && unicode.IsSpace(r)
+}
+
+func hasTrailingSpace(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return text != "" && unicode.IsSpace(r)
+}
+
+func terminal(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimRightFunc(text, unicode.IsSpace))
+	return terminalPunctuation[r]
+}
+
+func describeTerminal(r rune) string {
+	if r == 0 {
+		return "no punctuation"
+	}
+	return strconv.Quote(string(r))
+}
@@ -0,0 +1,164 @@
+package katolomb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestLint(t *testing.T) {
+	en, err := katolomb.NewYAMLTranslator([]byte(`
+greeting: "Hello %{name}!"
+padded: " Total: "
+settings: "Settings"
+description: "Manage the notifications you receive by email"
+quoted: 'Press "Save" (or cancel)'
+brand: "Our product"
+`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	es, err := katolomb.NewYAMLTranslator([]byte(`
+greeting: "¡Hola %{name}"
+padded: "Total:"
+settings: "Settings"
+description: "Gestiona"
+quoted: 'Pulsa "Guardar (o cancela]'
+brand: "Nuestro producto de pago"
+`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translators := map[string]katolomb.EnumerableTranslator{
+		"en": en.(katolomb.EnumerableTranslator),
+		"es": es.(katolomb.EnumerableTranslator),
+	}
+	rules := katolomb.DefaultLintRules()
+	rules["forbidden-terms"] = katolomb.NewForbiddenTermsRule(map[string][]string{"es": {"de pago"}, "*": {"TODO"}})
+	rules["custom"] = func(msg katolomb.LintMessage) []string {
+		if strings.HasPrefix(msg.Key, "brand") && msg.Locale == msg.SourceLocale {
+			return []string{"custom rule ran on source locale"}
+		}
+		return nil
+	}
+	if _, err := katolomb.Lint("fr", translators, rules); err == nil {
+		t.Errorf("expected Lint to return error when the source locale has no translator")
+	}
+	issues, err := katolomb.Lint("en", translators, rules)
+	if err != nil {
+		t.Fatalf("expected Lint not to return error, got %v", err)
+	}
+	expected := []katolomb.LintIssue{
+		{Rule: "custom", Locale: "en", Key: "brand", Message: "custom rule ran on source locale"},
+		{Rule: "forbidden-terms", Locale: "es", Key: "brand", Message: `contains forbidden term "de pago"`},
+		{Rule: "length-ratio", Locale: "es", Key: "brand", Message: "length is 2.18 times the source's"},
+		{Rule: "length-ratio", Locale: "es", Key: "description", Message: "length is 0.18 times the source's"},
+		{Rule: "punctuation", Locale: "es", Key: "greeting", Message: `ends with no punctuation but the source ends with "!"`},
+		{Rule: "whitespace", Locale: "es", Key: "padded", Message: "leading white space differs from the source's"},
+		{Rule: "whitespace", Locale: "es", Key: "padded", Message: "trailing white space differs from the source's"},
+		{Rule: "balance", Locale: "es", Key: "quoted", Message: `unbalanced quote "\""`},
+		{Rule: "balance", Locale: "es", Key: "quoted", Message: `unclosed "("`},
+		{Rule: "balance", Locale: "es", Key: "quoted", Message: `unexpected "]"`},
+		{Rule: "untranslated", Locale: "es", Key: "settings", Message: "identical to the source"},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected Lint to return\n%v\ngot\n%v", expected, issues)
+	}
+}
+
+func TestLintReports(t *testing.T) {
+	issues := []katolomb.LintIssue{
+		{Rule: "untranslated", Locale: "locales/es.yml", Key: "settings", Message: "identical to the source"},
+		{Rule: "balance", Locale: "locales/es.yml", Key: "quoted", Message: `unclosed "("`},
+	}
+	var buf bytes.Buffer
+	if err := katolomb.WriteLintReport(&buf, issues); err != nil {
+		t.Fatalf("expected WriteLintReport not to return error, got %v", err)
+	}
+	var report []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("expected WriteLintReport to write valid JSON, got %v", err)
+	}
+	expected := []map[string]string{
+		{"rule": "untranslated", "locale": "locales/es.yml", "key": "settings", "message": "identical to the source"},
+		{"rule": "balance", "locale": "locales/es.yml", "key": "quoted", "message": `unclosed "("`},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected WriteLintReport to write %v, got %v", expected, report)
+	}
+	buf.Reset()
+	katolomb.WriteLintReport(&buf, nil)
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected WriteLintReport to write an empty array with no issues, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := katolomb.WriteLintSARIF(&buf, issues); err != nil {
+		t.Fatalf("expected WriteLintSARIF not to return error, got %v", err)
+	}
+	var sarif struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+					}
+					LogicalLocations []struct{ FullyQualifiedName string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatalf("expected WriteLintSARIF to write valid JSON, got %v", err)
+	}
+	if sarif.Version != "2.1.0" || len(sarif.Runs) != 1 || sarif.Runs[0].Tool.Driver.Name != "katolomb" {
+		t.Fatalf("expected WriteLintSARIF to write a SARIF 2.1.0 log with a katolomb run, got %s", buf.Bytes())
+	}
+	run := sarif.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "balance" || run.Tool.Driver.Rules[1].ID != "untranslated" {
+		t.Errorf("expected WriteLintSARIF to list the sorted rules of the issues, got %v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected WriteLintSARIF to write a result per issue, got %d", len(run.Results))
+	}
+	result := run.Results[1]
+	if result.RuleID != "balance" || result.Level != "warning" || result.Message.Text != `unclosed "("` ||
+		result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "locales/es.yml" ||
+		result.Locations[0].LogicalLocations[0].FullyQualifiedName != "quoted" {
+		t.Errorf("expected WriteLintSARIF to write the issue's rule, message, locale and key, got %+v", result)
+	}
+}
+
+func TestLintBalanceGuillemets(t *testing.T) {
+	translators := make(map[string]katolomb.EnumerableTranslator)
+	for locale, text := range map[string]string{"en": `"Hello"`, "de": "»Hallo« „Welt“", "fr": "«Bonjour"} {
+		mt := katolomb.NewMemoryTranslator(".")
+		mt.Replace(map[string]string{"quoted": text})
+		translators[locale] = mt
+	}
+	rules := katolomb.LintRules{"balance": katolomb.DefaultLintRules()["balance"]}
+	issues, err := katolomb.Lint("en", translators, rules)
+	if err != nil {
+		t.Fatalf("expected Lint not to return error, got %v", err)
+	}
+	expected := []katolomb.LintIssue{
+		{Rule: "balance", Locale: "fr", Key: "quoted", Message: `unbalanced quotes "«" and "»"`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected Lint to return\n%v\ngot\n%v", expected, issues)
+	}
+}
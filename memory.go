@@ -0,0 +1,187 @@
+package katolomb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryTranslator is an EnumerableTranslator holding translations in memory
+// that can be set, deleted and replaced at runtime. It is safe for concurrent
+// use: writes copy the translations, so translations never wait for them.
+//
+// Keys are split with the translator's separator into routes in a tree of
+// translations, like the keys of the Translator returned by
+// NewYAMLTranslatorWithSeparator: keys leading to a group of translations
+// produce errors wrapping ErrIncompletePath, and keys with no translation
+// errors wrapping ErrKeyNotFound. Setting a translation whose route conflicts
+// with existing ones replaces them, so setting "a.b" removes the translation
+// for "a" and setting "a" removes the translations for "a.b" and "a.c".
+type MemoryTranslator struct {
+	separator string
+	mu        sync.Mutex
+	state     atomic.Value
+}
+
+type memoryState struct {
+	translations map[string]string
+	groups       map[string]bool
+}
+
+// NewMemoryTranslator takes a separator and returns an empty
+// MemoryTranslator splitting keys with it, with an empty separator meaning
+// keys are not split.
+func NewMemoryTranslator(separator string) *MemoryTranslator {
+	mt := &MemoryTranslator{separator: separator}
+	mt.state.Store(&memoryState{translations: map[string]string{}, groups: map[string]bool{}})
+	return mt
+}
+
+// Translate returns the translation for the key, or an error if there is
+// none.
+func (mt *MemoryTranslator) Translate(key string, properties TranslationProperties) (string, error) {
+	state := mt.load()
+	if translation, ok := state.translations[key]; ok {
+		return translation, nil
+	}
+	err := ErrKeyNotFound
+	if state.groups[key] {
+		err = ErrIncompletePath
+	}
+	return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), err)
+}
+
+// Keys returns the sorted list of keys with a translation.
+func (mt *MemoryTranslator) Keys() []string {
+	state := mt.load()
+	keys := make([]string, 0, len(state.translations))
+	for k := range state.translations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Set sets the translation for the key, replacing the translations whose
+// route conflicts with it.
+func (mt *MemoryTranslator) Set(key, translation string) {
+	mt.update(func(translations map[string]string) {
+		mt.deleteConflicts(translations, key)
+		translations[key] = translation
+	})
+}
+
+// Delete deletes the translation for the key, or all the translations in the
+// group of translations the key leads to.
+func (mt *MemoryTranslator) Delete(key string) {
+	mt.update(func(translations map[string]string) {
+		delete(translations, key)
+		mt.deleteGroup(translations, key)
+	})
+}
+
+// Replace replaces all the translations with the ones in the map parameter,
+// which holds translations indexed by their keys. Translations whose route
+// conflicts with a longer key (e.g. "a.b" along with "a.b.c") are left out in
+// favour of the longer one.
+func (mt *MemoryTranslator) Replace(translations map[string]string) {
+	keys := make([]string, 0, len(translations))
+	for k := range translations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	replaced := make(map[string]string, len(translations))
+	groups := make(map[string]bool)
+	for _, k := range keys {
+		if groups[k] {
+			continue
+		}
+		replaced[k] = translations[k]
+		mt.addGroups(groups, k)
+	}
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	mt.state.Store(&memoryState{translations: replaced, groups: groups})
+}
+
+// Snapshot returns a copy of the translations, indexed by their keys, that
+// can be passed to Replace to restore them.
+func (mt *MemoryTranslator) Snapshot() map[string]string {
+	state := mt.load()
+	snapshot := make(map[string]string, len(state.translations))
+	for k, v := range state.translations {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+func (mt *MemoryTranslator) load() *memoryState {
+	return mt.state.Load().(*memoryState)
+}
+
+func (mt *MemoryTranslator) update(f func(map[string]string)) {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	current := mt.load()
+	translations := make(map[string]string, len(current.translations)+1)
+	for k, v := range current.translations {
+		translations[k] = v
+	}
+	f(translations)
+	groups := make(map[string]bool)
+	for k := range translations {
+		mt.addGroups(groups, k)
+	}
+	mt.state.Store(&memoryState{translations: translations, groups: groups})
+}
+
+func (mt *MemoryTranslator) deleteConflicts(translations map[string]string, key string) {
+	mt.deleteGroup(translations, key)
+	for _, group := range mt.prefixes(key) {
+		delete(translations, group)
+	}
+}
+
+func (mt *MemoryTranslator) deleteGroup(translations map[string]string, key string) {
+	if mt.separator == "" {
+		return
+	}
+	prefix := key + mt.separator
+	for k := range translations {
+		if strings.HasPrefix(k, prefix) {
+			delete(translations, k)
+		}
+	}
+}
+
+func (mt *MemoryTranslator) addGroups(groups map[string]bool, key string) {
+	for _, group := range mt.prefixes(key) {
+		groups[group] = true
+	}
+}
+
+// prefixes returns the keys of the groups of translations on the route to the
+// key.
+func (mt *MemoryTranslator) prefixes(key string) []string {
+	if mt.separator == "" {
+		return nil
+	}
+	var prefixes []string
+	for i := 0; ; {
+		next := strings.Index(key[i:], mt.separator)
+		if next < 0 {
+			return prefixes
+		}
+		i += next
+		prefixes = append(prefixes, key[:i])
+		i += len(mt.separator)
+	}
+}
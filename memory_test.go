@@ -0,0 +1,110 @@
+package katolomb_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestMemoryTranslator(t *testing.T) {
+	mt := katolomb.NewMemoryTranslator(".")
+	mt.Set("en.hello", "Hello!")
+	mt.Set("en.bye", "Bye!")
+	mt.Set("en.my.message", "How are you?")
+	props := katolomb.NewTranslationProperties(nil)
+	testCases := []struct {
+		key      string
+		expected string
+		err      error
+	}{
+		{"en.hello", "Hello!", nil},
+		{"en.my.message", "How are you?", nil},
+		{"en.my", "", katolomb.ErrIncompletePath},
+		{"en", "", katolomb.ErrIncompletePath},
+		{"en.missing", "", katolomb.ErrKeyNotFound},
+		{"en.hello.world", "", katolomb.ErrKeyNotFound},
+	}
+	for _, tc := range testCases {
+		result, err := mt.Translate(tc.key, props)
+		if result != tc.expected || !errors.Is(err, tc.err) {
+			t.Errorf("expected Translate to return %q and %v for %v, got %q and %v", tc.expected, tc.err, tc.key, result, err)
+		}
+	}
+	if keys := mt.Keys(); !reflect.DeepEqual(keys, []string{"en.bye", "en.hello", "en.my.message"}) {
+		t.Errorf("expected Keys to return the keys set, got %v", keys)
+	}
+
+	snapshot := mt.Snapshot()
+	mt.Set("en.my", "Mine")
+	if keys := mt.Keys(); !reflect.DeepEqual(keys, []string{"en.bye", "en.hello", "en.my"}) {
+		t.Errorf("expected Set to replace the group of translations at the key, got %v", keys)
+	}
+	mt.Set("en.hello.world", "Hello world!")
+	if _, err := mt.Translate("en.hello", props); !errors.Is(err, katolomb.ErrIncompletePath) {
+		t.Errorf("expected Set to replace the translation on the route to the key, got %v", err)
+	}
+	mt.Delete("en.hello")
+	mt.Delete("en.bye")
+	if keys := mt.Keys(); !reflect.DeepEqual(keys, []string{"en.my"}) {
+		t.Errorf("expected Delete to delete translations and groups of translations, got %v", keys)
+	}
+
+	mt.Replace(snapshot)
+	if !reflect.DeepEqual(mt.Snapshot(), snapshot) {
+		t.Errorf("expected Replace to restore the snapshot %v, got %v", snapshot, mt.Snapshot())
+	}
+	snapshot["en.hello"] = "Hi!"
+	if result, _ := mt.Translate("en.hello", props); result != "Hello!" {
+		t.Errorf("expected changes to the snapshot not to affect the translator, got %q", result)
+	}
+	mt.Replace(map[string]string{"a": "A", "a.b": "AB", "a.b.c": "ABC", "d": "D"})
+	if keys := mt.Keys(); !reflect.DeepEqual(keys, []string{"a.b.c", "d"}) {
+		t.Errorf("expected Replace to keep the longest of conflicting keys, got %v", keys)
+	}
+}
+
+func TestMemoryTranslatorWithEmptySeparator(t *testing.T) {
+	mt := katolomb.NewMemoryTranslator("")
+	mt.Set("en.hello", "Hello!")
+	mt.Set("en", "English")
+	props := katolomb.NewTranslationProperties(nil)
+	for key, expected := range map[string]string{"en.hello": "Hello!", "en": "English"} {
+		if result, err := mt.Translate(key, props); result != expected || err != nil {
+			t.Errorf("expected Translate to return %q for %v with an empty separator, got %q and %v", expected, key, result, err)
+		}
+	}
+}
+
+func TestMemoryTranslatorConcurrency(t *testing.T) {
+	mt := katolomb.NewMemoryTranslator(".")
+	props := katolomb.NewTranslationProperties(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("en.%d.%d", i, j)
+				mt.Set(key, key)
+				if j%10 == 0 {
+					mt.Delete(key)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mt.Translate("en.0.1", props)
+				mt.Keys()
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(mt.Keys()); n != 8*90 {
+		t.Errorf("expected concurrent writes to leave %d translations, got %d", 8*90, n)
+	}
+}
@@ -0,0 +1,107 @@
+package katolomb
+
+// Message is a Translatable made of a key, the properties to translate it
+// with and an optional default translation. Properties are either strings or
+// Translatable values, which are translated with the same Translator as the
+// message when the translation requires them.
+//
+// Messages are immutable: their With methods return a modified copy, so a
+// message can be shared and extended safely.
+type Message struct {
+	key                string
+	properties         map[string]interface{}
+	defaultTranslation string
+	hasDefault         bool
+}
+
+// NewMessage takes a key and returns a Message for the key with no
+// properties and no default translation.
+func NewMessage(key string) Message {
+	return Message{key: key}
+}
+
+// With returns a copy of the Message with the property of the given name set
+// to the value parameter.
+func (m Message) With(name, value string) Message {
+	return m.with(name, value)
+}
+
+// WithTranslatable returns a copy of the Message with the property of the
+// given name set to the translation of the Translatable parameter.
+func (m Message) WithTranslatable(name string, value Translatable) Message {
+	return m.with(name, value)
+}
+
+// WithProperties returns a copy of the Message with the properties in the
+// map parameter set.
+func (m Message) WithProperties(properties map[string]string) Message {
+	c := m.copy(len(properties))
+	for name, value := range properties {
+		c.properties[name] = value
+	}
+	return c
+}
+
+// WithDefault returns a copy of the Message that translates to the
+// translation parameter when it cannot be translated.
+func (m Message) WithDefault(translation string) Message {
+	m.defaultTranslation = translation
+	m.hasDefault = true
+	return m
+}
+
+// Key returns the key of the Message.
+func (m Message) Key() string {
+	return m.key
+}
+
+// Properties returns a copy of the properties of the Message, each one either
+// a string or a Translatable.
+func (m Message) Properties() map[string]interface{} {
+	properties := make(map[string]interface{}, len(m.properties))
+	for name, value := range m.properties {
+		properties[name] = value
+	}
+	return properties
+}
+
+// Translate returns the translation of the Message's key by the Translator
+// parameter with the Message's properties. Translatable properties are
+// translated with the same Translator when requested, and errors translating
+// them are returned as the error of the property. If the translation returns
+// an error and the Message has a default translation, the default translation
+// is returned instead.
+func (m Message) Translate(translator Translator) (string, error) {
+	props := TranslationPropertiesFunc(func(name string) (string, error) {
+		switch value := m.properties[name].(type) {
+		case string:
+			return value, nil
+		case Translatable:
+			return value.Translate(translator)
+		}
+		return "", ErrPropertyMissing
+	})
+	t, err := translator.Translate(m.key, props)
+	if err != nil {
+		if m.hasDefault {
+			return m.defaultTranslation, nil
+		}
+		return "", err
+	}
+	return t, nil
+}
+
+func (m Message) with(name string, value interface{}) Message {
+	c := m.copy(1)
+	c.properties[name] = value
+	return c
+}
+
+func (m Message) copy(extra int) Message {
+	properties := make(map[string]interface{}, len(m.properties)+extra)
+	for name, value := range m.properties {
+		properties[name] = value
+	}
+	m.properties = properties
+	return m
+}
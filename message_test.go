@@ -0,0 +1,57 @@
+package katolomb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestMessage(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`
+greeting: "Hello %{name}, you have %{inbox}"
+inbox: "%{count} new messages in %{folder|your inbox}"
+folder: "the %{name} folder"
+plain: "No properties"
+`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(yt, katolomb.NewInterpolator())
+	inbox := katolomb.NewMessage("inbox").With("count", "3")
+	testCases := []struct {
+		message     katolomb.Translatable
+		expected    string
+		err         error
+		description string
+	}{
+		{katolomb.NewMessage("plain"), "No properties", nil, "the message has no properties"},
+		{inbox, "3 new messages in your inbox", nil, "the message has string properties"},
+		{inbox.WithTranslatable("folder", katolomb.NewMessage("folder").With("name", "work")), "3 new messages in the work folder", nil, "the message has a translatable property"},
+		{katolomb.NewMessage("greeting").WithProperties(map[string]string{"name": "John"}).WithTranslatable("inbox", inbox), "Hello John, you have 3 new messages in your inbox", nil, "the message has nested translatable properties"},
+		{katolomb.NewMessage("greeting").With("name", "John").WithTranslatable("inbox", katolomb.NewMessage("inbox")), "", katolomb.ErrPropertyMissing, "a nested translatable property lacks a property"},
+		{katolomb.NewMessage("greeting").With("name", "John").WithTranslatable("inbox", katolomb.NewMessage("inbox")).WithDefault("Hello!"), "Hello!", nil, "a message with default fails to translate"},
+		{katolomb.NewMessage("missing"), "", katolomb.ErrKeyNotFound, "the key is missing"},
+	}
+	for _, tc := range testCases {
+		result, err := tc.message.Translate(translator)
+		if result != tc.expected || !errors.Is(err, tc.err) {
+			t.Errorf("expected Translate to return %q and %v when %v, got %q and %v", tc.expected, tc.err, tc.description, result, err)
+		}
+	}
+}
+
+func TestMessageImmutability(t *testing.T) {
+	base := katolomb.NewMessage("inbox").With("count", "3")
+	extended := base.With("folder", "work").With("count", "4")
+	if base.Key() != "inbox" || len(base.Properties()) != 1 || base.Properties()["count"] != "3" {
+		t.Errorf("expected With not to modify the original message, got %v with %v", base.Key(), base.Properties())
+	}
+	if len(extended.Properties()) != 2 || extended.Properties()["count"] != "4" {
+		t.Errorf("expected With to return a message with the new properties, got %v", extended.Properties())
+	}
+	base.Properties()["count"] = "5"
+	if base.Properties()["count"] != "3" {
+		t.Errorf("expected Properties to return a copy of the message's properties")
+	}
+}
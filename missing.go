@@ -0,0 +1,155 @@
+package katolomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MissingKey holds the record of a key that could not be translated.
+//
+// Locale is the locale of the recorder, Count the number of failed
+// translations of the key, FirstSeen and LastSeen the times of the first and
+// last failure and Err the error returned by the last failure.
+type MissingKey struct {
+	Locale    string
+	Key       string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Err       error
+}
+
+// MissingKeyRecorder is a Translator that wraps another Translator to record
+// the keys it fails to translate. It is safe for concurrent use.
+type MissingKeyRecorder struct {
+	translator ContextTranslator
+	locale     string
+	mu         sync.Mutex
+	missing    map[string]*MissingKey
+}
+
+type missingKeyJSON struct {
+	Locale    string    `json:"locale"`
+	Key       string    `json:"key"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Error     string    `json:"error"`
+}
+
+// NewMissingKeyRecorder takes a Translator and the locale of its translations
+// and returns a MissingKeyRecorder wrapping it.
+//
+// Since the recorder returns the errors of the wrapped Translator, it should
+// be placed inside wrappers that hide errors, such as the one returned by
+// NewKeyAsDefaultTranslator, so that misses are still recorded.
+func NewMissingKeyRecorder(translator Translator, locale string) *MissingKeyRecorder {
+	return &MissingKeyRecorder{
+		translator: AsContextTranslator(translator),
+		locale:     locale,
+		missing:    make(map[string]*MissingKey),
+	}
+}
+
+// Translate returns the result of the wrapped Translator's Translate method,
+// recording the key when it returns an error.
+func (mkr *MissingKeyRecorder) Translate(key string, props TranslationProperties) (string, error) {
+	return mkr.TranslateContext(context.Background(), key, props)
+}
+
+// TranslateContext behaves like Translate, passing the context to the wrapped
+// Translator (see AsContextTranslator). Keys failing because the context is
+// done are not recorded.
+func (mkr *MissingKeyRecorder) TranslateContext(ctx context.Context, key string, props TranslationProperties) (string, error) {
+	t, err := mkr.translator.TranslateContext(ctx, key, props)
+	if err != nil && ctx.Err() == nil {
+		mkr.record(key, err)
+	}
+	return t, err
+}
+
+func (mkr *MissingKeyRecorder) record(key string, err error) {
+	now := time.Now()
+	mkr.mu.Lock()
+	defer mkr.mu.Unlock()
+	mk, ok := mkr.missing[key]
+	if !ok {
+		mk = &MissingKey{Locale: mkr.locale, Key: key, FirstSeen: now}
+		mkr.missing[key] = mk
+	}
+	mk.Count++
+	mk.LastSeen = now
+	mk.Err = err
+}
+
+// Snapshot returns the records of the keys that failed to translate so far,
+// sorted by key.
+func (mkr *MissingKeyRecorder) Snapshot() []MissingKey {
+	mkr.mu.Lock()
+	snapshot := make([]MissingKey, 0, len(mkr.missing))
+	for _, mk := range mkr.missing {
+		snapshot = append(snapshot, *mk)
+	}
+	mkr.mu.Unlock()
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Key < snapshot[j].Key
+	})
+	return snapshot
+}
+
+// Reset discards the records of the keys that failed to translate so far.
+func (mkr *MissingKeyRecorder) Reset() {
+	mkr.mu.Lock()
+	defer mkr.mu.Unlock()
+	mkr.missing = make(map[string]*MissingKey)
+}
+
+// ServeHTTP responds with a JSON array with the records in the recorder's
+// Snapshot, each one as an object with "locale", "key", "count",
+// "first_seen", "last_seen" and "error" attributes.
+func (mkr *MissingKeyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := mkr.Snapshot()
+	records := make([]missingKeyJSON, 0, len(snapshot))
+	for _, mk := range snapshot {
+		record := missingKeyJSON{
+			Locale:    mk.Locale,
+			Key:       mk.Key,
+			Count:     mk.Count,
+			FirstSeen: mk.FirstSeen,
+			LastSeen:  mk.LastSeen,
+		}
+		if mk.Err != nil {
+			record.Error = mk.Err.Error()
+		}
+		records = append(records, record)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// WriteYAMLStub writes to w a YAML document in the format read by
+// NewYAMLTranslatorWithSeparator with the given separator, holding an empty
+// translation for each key in the recorder's Snapshot, for translators to
+// fill. Keys whose route conflicts with a longer key (e.g. "a.b" along with
+// "a.b.c") are left out in favour of the longer one.
+func (mkr *MissingKeyRecorder) WriteYAMLStub(w io.Writer, separator string) error {
+	snapshot := mkr.Snapshot()
+	empty := make(map[string]string, len(snapshot))
+	for _, mk := range snapshot {
+		empty[mk.Key] = ""
+	}
+	yml, err := yaml.Marshal(nestTranslations(empty, separator))
+	if err != nil {
+		return fmt.Errorf("marshalling yaml stub: %v", err)
+	}
+	_, err = w.Write(yml)
+	return err
+}
@@ -0,0 +1,100 @@
+package katolomb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestMissingKeyRecorder(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"en": {"hello": "Hello!"}}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	recorder := katolomb.NewMissingKeyRecorder(yt, "en")
+	translator := katolomb.NewKeyAsDefaultTranslator(recorder)
+	props := katolomb.NewTranslationProperties(nil)
+	for _, key := range []string{"en.hello", "en.bye", "en.my.message", "en.bye", "en.hello"} {
+		translator.Translate(key, props)
+	}
+	if _, err := recorder.Translate("en.bye", props); !errors.Is(err, katolomb.ErrKeyNotFound) {
+		t.Errorf("expected Translate to return the wrapped translator's error, got %v", err)
+	}
+	snapshot := recorder.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected Snapshot to return 2 records, got %d", len(snapshot))
+	}
+	testCases := []struct {
+		key   string
+		count int
+	}{
+		{"en.bye", 3},
+		{"en.my.message", 1},
+	}
+	for i, tc := range testCases {
+		mk := snapshot[i]
+		if mk.Key != tc.key || mk.Count != tc.count || mk.Locale != "en" {
+			t.Errorf("expected record %d to be for %v in en with count %d, got %v in %v with count %d", i, tc.key, tc.count, mk.Key, mk.Locale, mk.Count)
+		}
+		if mk.FirstSeen.IsZero() || mk.LastSeen.Before(mk.FirstSeen) {
+			t.Errorf("expected record for %v to have consistent first and last seen times, got %v and %v", mk.Key, mk.FirstSeen, mk.LastSeen)
+		}
+		if !errors.Is(mk.Err, katolomb.ErrKeyNotFound) {
+			t.Errorf("expected record for %v to keep the underlying error, got %v", mk.Key, mk.Err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	recorder.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected ServeHTTP to respond with JSON content type, got %v", ct)
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil {
+		t.Fatalf("expected ServeHTTP to respond with valid JSON, got %v", err)
+	}
+	if len(records) != 2 || records[0]["key"] != "en.bye" || records[0]["count"] != float64(3) || records[0]["locale"] != "en" || records[0]["error"] == "" {
+		t.Errorf("expected ServeHTTP to respond with the snapshot records, got %v", records)
+	}
+
+	var stub bytes.Buffer
+	if err := recorder.WriteYAMLStub(&stub, "."); err != nil {
+		t.Fatalf("expected WriteYAMLStub not to return error, got %v", err)
+	}
+	st, err := katolomb.NewYAMLTranslatorWithOptions(stub.Bytes(), katolomb.DefaultYAMLOptions())
+	if err != nil {
+		t.Fatalf("expected the YAML stub to be loadable, got %v", err)
+	}
+	for _, tc := range testCases {
+		if result, err := st.Translate(tc.key, props); err != nil || result != "" {
+			t.Errorf("expected the YAML stub to hold an empty translation for %v, got %q, %v", tc.key, result, err)
+		}
+	}
+
+	recorder.Reset()
+	if snapshot := recorder.Snapshot(); len(snapshot) != 0 {
+		t.Errorf("expected Snapshot to return no records after Reset, got %v", snapshot)
+	}
+}
+
+func TestMissingKeyRecorderYAMLStubConflicts(t *testing.T) {
+	recorder := katolomb.NewMissingKeyRecorder(katolomb.TranslatorFunc(func(string, katolomb.TranslationProperties) (string, error) {
+		return "", errors.New("some error")
+	}), "es")
+	for _, key := range []string{"a.b", "a.b.c", "a.d"} {
+		recorder.Translate(key, nil)
+	}
+	var stub bytes.Buffer
+	if err := recorder.WriteYAMLStub(&stub, "."); err != nil {
+		t.Fatalf("expected WriteYAMLStub not to return error, got %v", err)
+	}
+	expected := "a:\n  b:\n    c: \"\"\n  d: \"\"\n"
+	if stub.String() != expected {
+		t.Errorf("expected WriteYAMLStub to write %q, got %q", expected, stub.String())
+	}
+}
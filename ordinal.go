@@ -0,0 +1,167 @@
+package katolomb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OrdinalModifier is the suffix that, appended to a property name with a ':'
+// (e.g. %{rank:ordinal}), makes the TranslationProperties returned by
+// NewOrdinalTranslationProperties format the property as an ordinal number.
+const OrdinalModifier = "ordinal"
+
+// OrdinalCategories lists the CLDR ordinal plural categories.
+var OrdinalCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+var ordinalRules = map[string]func(n uint64) string{
+	"en": func(n uint64) string {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 == 2 && n%100 != 12:
+			return "two"
+		case n%10 == 3 && n%100 != 13:
+			return "few"
+		}
+		return "other"
+	},
+	"fr": func(n uint64) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	"it": func(n uint64) string {
+		switch n {
+		case 8, 11, 80, 800:
+			return "many"
+		}
+		return "other"
+	},
+	"ca": func(n uint64) string {
+		switch n {
+		case 1, 3:
+			return "one"
+		case 2:
+			return "two"
+		case 4:
+			return "few"
+		}
+		return "other"
+	},
+	"sv": func(n uint64) string {
+		if (n%10 == 1 || n%10 == 2) && n%100 != 11 && n%100 != 12 {
+			return "one"
+		}
+		return "other"
+	},
+	"hu": func(n uint64) string {
+		if n == 1 || n == 5 {
+			return "one"
+		}
+		return "other"
+	},
+}
+
+var ordinalSuffixes = map[string]map[string]string{
+	"en": {"one": "st", "two": "nd", "few": "rd", "other": "th"},
+	"fr": {"one": "er", "other": "e"},
+	"es": {"other": "º"},
+	"pt": {"other": "º"},
+	"it": {"other": "º"},
+	"ca": {"one": "r", "two": "n", "few": "t", "other": "è"},
+	"de": {"other": "."},
+	"nl": {"other": "e"},
+	"sv": {"one": ":a", "other": ":e"},
+	"hu": {"other": "."},
+}
+
+// OrdinalCategory returns the CLDR ordinal plural category ("one", "two",
+// "few", "many" or "other") of the integer n in the given locale. Locales are
+// matched by their language (e.g. "en-GB" and "en_US" as "en"), and locales
+// without specific rules always get the "other" category.
+func OrdinalCategory(locale string, n int64) string {
+	rule, ok := ordinalRules[localeLanguage(locale)]
+	if !ok {
+		return "other"
+	}
+	if n < 0 {
+		return rule(uint64(-n))
+	}
+	return rule(uint64(n))
+}
+
+// Ordinal returns the integer n formatted as an ordinal number in the given
+// locale (e.g. "1st", "2nd" and "3rd" in English), using built-in suffix
+// tables for English, French, Spanish, Portuguese, Italian, Catalan, German,
+// Dutch, Swedish and Hungarian. Numbers in other locales are returned without
+// a suffix.
+func Ordinal(locale string, n int64) string {
+	suffixes := ordinalSuffixes[localeLanguage(locale)]
+	suffix, ok := suffixes[OrdinalCategory(locale, n)]
+	if !ok {
+		suffix = suffixes["other"]
+	}
+	return strconv.FormatInt(n, 10) + suffix
+}
+
+// NewOrdinalTranslator takes a Translator, a locale and a property name and
+// returns a new Translator that wraps the Translator parameter to pick
+// translation variants under an "ordinal" subtree with the CLDR ordinal
+// category of the property's integer value in the locale, so that a "place"
+// key may have "place.ordinal.one", "place.ordinal.two", "place.ordinal.few"
+// and "place.ordinal.other" translations.
+//
+// See NewSelectTranslatorWithOptions for details on how variants are looked
+// up.
+func NewOrdinalTranslator(translator Translator, locale, property string) Translator {
+	return NewSelectTranslatorWithOptions(translator, SelectOptions{
+		Property:  property,
+		Separator: ".",
+		Fallback:  "other",
+		Values:    OrdinalCategories,
+		Subtree:   "ordinal",
+		Variant: func(value string) (string, error) {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return "", err
+			}
+			return OrdinalCategory(locale, n), nil
+		},
+	})
+}
+
+// NewOrdinalTranslationProperties takes a TranslationProperties and a locale
+// and returns a TranslationProperties that wraps the TranslationProperties
+// parameter to provide properties whose name ends with the ":ordinal"
+// modifier (e.g. "rank:ordinal") as the ordinal number in the locale of the
+// property named without the modifier. The remaining properties are provided
+// by the wrapped TranslationProperties.
+//
+// Combined with the Interpolator returned by NewInterpolator, it allows
+// interpolation declarations such as %{rank:ordinal}.
+func NewOrdinalTranslationProperties(ps TranslationProperties, locale string) TranslationProperties {
+	return TranslationPropertiesFunc(func(p string) (string, error) {
+		name := strings.TrimSuffix(p, ":"+OrdinalModifier)
+		if name == p {
+			return ps.Property(p)
+		}
+		v, err := ps.Property(name)
+		if err != nil {
+			return "", err
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("formatting %v as ordinal: %w", strconv.Quote(name), err)
+		}
+		return Ordinal(locale, n), nil
+	})
+}
+
+func localeLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
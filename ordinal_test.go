@@ -0,0 +1,115 @@
+package katolomb_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestOrdinal(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		n        int64
+		category string
+		ordinal  string
+	}{
+		{"en", 1, "one", "1st"},
+		{"en", 2, "two", "2nd"},
+		{"en", 3, "few", "3rd"},
+		{"en", 4, "other", "4th"},
+		{"en", 11, "other", "11th"},
+		{"en", 12, "other", "12th"},
+		{"en", 13, "other", "13th"},
+		{"en-GB", 21, "one", "21st"},
+		{"en_US", 102, "two", "102nd"},
+		{"en", 113, "other", "113th"},
+		{"fr", 1, "one", "1er"},
+		{"fr", 2, "other", "2e"},
+		{"es", 3, "other", "3º"},
+		{"it", 11, "many", "11º"},
+		{"ca", 3, "one", "3r"},
+		{"ca", 2, "two", "2n"},
+		{"ca", 4, "few", "4t"},
+		{"ca", 5, "other", "5è"},
+		{"de", 7, "other", "7."},
+		{"sv", 22, "one", "22:a"},
+		{"sv", 12, "other", "12:e"},
+		{"ja", 1, "other", "1"},
+	}
+	for _, tc := range testCases {
+		if c := katolomb.OrdinalCategory(tc.locale, tc.n); c != tc.category {
+			t.Errorf("expected OrdinalCategory to return %v for %d in %v, got %v", strconv.Quote(tc.category), tc.n, tc.locale, strconv.Quote(c))
+		}
+		if o := katolomb.Ordinal(tc.locale, tc.n); o != tc.ordinal {
+			t.Errorf("expected Ordinal to return %v for %d in %v, got %v", strconv.Quote(tc.ordinal), tc.n, tc.locale, strconv.Quote(o))
+		}
+	}
+}
+
+func TestNewOrdinalTranslator(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`---
+place:
+  ordinal:
+    one: "%{rank}st place"
+    two: "%{rank}nd place"
+    few: "%{rank}rd place"
+    other: "%{rank}th place"
+title: Leaderboard`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(katolomb.NewOrdinalTranslator(yt, "en", "rank"), katolomb.NewInterpolator())
+	testCases := []struct {
+		key    string
+		rank   string
+		result string
+	}{
+		{"place", "1", "1st place"},
+		{"place", "22", "22nd place"},
+		{"place", "103", "103rd place"},
+		{"place", "11", "11th place"},
+		{"place", "first", "firstth place"},
+		{"title", "1", "Leaderboard"},
+	}
+	for _, tc := range testCases {
+		result, err := translator.Translate(tc.key, katolomb.NewTranslationProperties(map[string]string{"rank": tc.rank}))
+		if err != nil {
+			t.Errorf("expected Translate not to return error for %v with rank %v, got %v", tc.key, tc.rank, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Translate to return %v for %v with rank %v, got %v", strconv.Quote(tc.result), tc.key, tc.rank, strconv.Quote(result))
+		}
+	}
+	expected := []string{"place", "title"}
+	if keys := katolomb.NewOrdinalTranslator(yt, "en", "rank").(katolomb.EnumerableTranslator).Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected Keys to return %v, got %v", expected, keys)
+	}
+}
+
+func TestNewOrdinalTranslationProperties(t *testing.T) {
+	props := katolomb.NewTranslationProperties(map[string]string{"rank": "2", "name": "Kató", "bad": "second"})
+	testCases := []struct {
+		locale    string
+		text      string
+		result    string
+		errNotNil bool
+	}{
+		{"en", "%{name} finished %{rank:ordinal}", "Kató finished 2nd", false},
+		{"fr", "%{name} a fini %{rank:ordinal}", "Kató a fini 2e", false},
+		{"en", "%{missing:ordinal}", "", true},
+		{"en", "%{bad:ordinal}", "", true},
+		{"en", "%{missing:ordinal|last}", "last", false},
+	}
+	for _, tc := range testCases {
+		result, err := katolomb.NewInterpolator().Interpolate(tc.text, katolomb.NewOrdinalTranslationProperties(props, tc.locale))
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			t.Errorf("expected Interpolate to return error (%v) for %v in %v, got %v", tc.errNotNil, strconv.Quote(tc.text), tc.locale, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Interpolate to return %v for %v in %v, got %v", strconv.Quote(tc.result), strconv.Quote(tc.text), tc.locale, strconv.Quote(result))
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package katolomb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderIssueKind identifies the kind of a PlaceholderIssue.
+type PlaceholderIssueKind int
+
+const (
+	// PlaceholderMissing is the kind of issues about properties declared in
+	// the reference locale's translation of a key but not in another
+	// locale's.
+	PlaceholderMissing PlaceholderIssueKind = iota
+	// PlaceholderExtra is the kind of issues about properties declared in a
+	// locale's translation of a key but not in the reference locale's.
+	PlaceholderExtra
+	// PlaceholderDefault is the kind of issues about properties with a
+	// default value in only one of the reference locale's translation of a
+	// key and another locale's.
+	PlaceholderDefault
+	// PlaceholderMalformed is the kind of issues about malformed interpolation
+	// declarations, such as an unterminated "%{name".
+	PlaceholderMalformed
+)
+
+// PlaceholderIssue describes an inconsistent or malformed interpolation
+// declaration in the translation of a key.
+//
+// Locale is the locale of the translation with the issue, Reference the
+// locale it was compared with, Key the key of the translation, Kind the kind
+// of issue, Property the name of the property involved, if any, and
+// Declaration the malformed declaration, for PlaceholderMalformed issues.
+type PlaceholderIssue struct {
+	Locale      string
+	Reference   string
+	Key         string
+	Kind        PlaceholderIssueKind
+	Property    string
+	Declaration string
+}
+
+// String returns the name of the PlaceholderIssueKind.
+func (k PlaceholderIssueKind) String() string {
+	switch k {
+	case PlaceholderMissing:
+		return "missing"
+	case PlaceholderExtra:
+		return "extra"
+	case PlaceholderDefault:
+		return "default"
+	case PlaceholderMalformed:
+		return "malformed"
+	}
+	return fmt.Sprintf("PlaceholderIssueKind(%d)", int(k))
+}
+
+// String returns a description of the issue prefixed by its locale and key.
+func (pi PlaceholderIssue) String() string {
+	var desc string
+	switch pi.Kind {
+	case PlaceholderMissing:
+		desc = fmt.Sprintf("property %v declared in %v is missing", strconv.Quote(pi.Property), pi.Reference)
+	case PlaceholderExtra:
+		desc = fmt.Sprintf("property %v is not declared in %v", strconv.Quote(pi.Property), pi.Reference)
+	case PlaceholderDefault:
+		desc = fmt.Sprintf("property %v has a default value in only one of this locale and %v", strconv.Quote(pi.Property), pi.Reference)
+	default:
+		desc = fmt.Sprintf("malformed declaration %v", strconv.Quote(pi.Declaration))
+	}
+	return fmt.Sprintf("%v: %v: %v", pi.Locale, strconv.Quote(pi.Key), desc)
+}
+
+// CheckPlaceholders takes the name of a reference locale and a map of locales
+// to EnumerableTranslator, which must include the reference locale, and
+// returns the issues with the interpolation declarations, in the format of
+// the Interpolator returned by NewInterpolator, of their translations, sorted
+// by key and locale.
+//
+// For each key translated in both the reference locale and another locale,
+// the properties declared in both translations are compared, reporting
+// properties missing or extra in the other locale and properties with a
+// default value in only one of them. Default values are not compared, since
+// they are usually translated. Malformed declarations are reported for the
+// translations of every locale.
+//
+// Translations are obtained with no properties available, so translators
+// must return them uninterpolated.
+func CheckPlaceholders(reference string, translators map[string]EnumerableTranslator) ([]PlaceholderIssue, error) {
+	ref, ok := translators[reference]
+	if !ok {
+		return nil, fmt.Errorf("checking placeholders: no translator for reference locale %v", reference)
+	}
+	refTexts := placeholderTexts(ref)
+	var issues []PlaceholderIssue
+	for locale, translator := range translators {
+		texts := refTexts
+		if locale != reference {
+			texts = placeholderTexts(translator)
+		}
+		for key, text := range texts {
+			for _, d := range malformedDeclarations(text) {
+				issues = append(issues, PlaceholderIssue{Locale: locale, Reference: reference, Key: key, Kind: PlaceholderMalformed, Declaration: d})
+			}
+			refText, ok := refTexts[key]
+			if !ok || locale == reference {
+				continue
+			}
+			issues = append(issues, compareDeclarations(locale, reference, key, text, refText)...)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Property != b.Property {
+			return a.Property < b.Property
+		}
+		return a.Declaration < b.Declaration
+	})
+	return issues, nil
+}
+
+func placeholderTexts(translator EnumerableTranslator) map[string]string {
+	texts := make(map[string]string)
+	props := NewTranslationProperties(nil)
+	for _, key := range translator.Keys() {
+		text, err := translator.Translate(key, props)
+		if err == nil {
+			texts[key] = text
+		}
+	}
+	return texts
+}
+
+func compareDeclarations(locale, reference, key, text, refText string) []PlaceholderIssue {
+	defaults := declaredProperties(text)
+	refDefaults := declaredProperties(refText)
+	swallowed := swallowedProperties(text)
+	for property := range swallowedProperties(refText) {
+		swallowed[property] = true
+	}
+	var issues []PlaceholderIssue
+	for property, hasDefault := range refDefaults {
+		otherHasDefault, ok := defaults[property]
+		switch {
+		case swallowed[property]:
+		case !ok:
+			issues = append(issues, PlaceholderIssue{Locale: locale, Reference: reference, Key: key, Kind: PlaceholderMissing, Property: property})
+		case hasDefault != otherHasDefault:
+			issues = append(issues, PlaceholderIssue{Locale: locale, Reference: reference, Key: key, Kind: PlaceholderDefault, Property: property})
+		}
+	}
+	for property := range defaults {
+		if _, ok := refDefaults[property]; !ok && !swallowed[property] {
+			issues = append(issues, PlaceholderIssue{Locale: locale, Reference: reference, Key: key, Kind: PlaceholderExtra, Property: property})
+		}
+	}
+	return issues
+}
+
+// declaredProperties returns the properties declared in the text, and whether
+// all their declarations have a default value. Declarations swallowing an
+// unterminated one, reported by malformedDeclarations, are ignored.
+func declaredProperties(text string) map[string]bool {
+	properties := make(map[string]bool)
+	for _, d := range ParseDeclarations(text) {
+		if strings.Contains(d.Text[2:], "%{") {
+			continue
+		}
+		hasDefault, ok := properties[d.Property]
+		properties[d.Property] = d.HasDefaultValue && (!ok || hasDefault)
+	}
+	return properties
+}
+
+// swallowedProperties returns the properties involved in declarations
+// swallowing an unterminated one (e.g. "x" and "y" for "%{x %{y}"), which are
+// reported by malformedDeclarations and so are not compared.
+func swallowedProperties(text string) map[string]bool {
+	properties := make(map[string]bool)
+	for _, d := range ParseDeclarations(text) {
+		body := d.Text[2 : len(d.Text)-1]
+		if !strings.Contains(body, "%{") {
+			continue
+		}
+		for _, fragment := range strings.Split(body, "%{") {
+			if i := strings.IndexByte(fragment, '|'); i >= 0 {
+				fragment = fragment[:i]
+			}
+			properties[strings.TrimRight(fragment, " ")] = true
+		}
+	}
+	return properties
+}
+
+// malformedDeclarations returns the fragments of the text starting with "%{"
+// that do not start an interpolation declaration, up to the next "}" or the
+// end of the text. A "%{" followed by another one before its closing "}"
+// (e.g. "%{x %{y}") is unterminated, and reported up to the next "%{".
+func malformedDeclarations(text string) []string {
+	var malformed []string
+	for i := 0; ; {
+		next := strings.Index(text[i:], "%{")
+		if next < 0 {
+			return malformed
+		}
+		i += next
+		m := defaultVanillaInterpolatorRegexp.FindStringIndex(text[i:])
+		if m != nil && m[0] == 0 {
+			inner := strings.Index(text[i+2:i+m[1]], "%{")
+			if inner < 0 {
+				i += m[1]
+				continue
+			}
+			malformed = append(malformed, strings.TrimRight(text[i:i+2+inner], " "))
+		} else {
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				end = len(text) - i - 1
+			}
+			malformed = append(malformed, text[i:i+end+1])
+		}
+		i += 2
+	}
+}
@@ -0,0 +1,70 @@
+package katolomb_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestCheckPlaceholders(t *testing.T) {
+	en, err := katolomb.NewYAMLTranslator([]byte(`
+greeting: "Hello %{name}, %{title|friend}"
+inbox: "You have %{count} messages"
+broken: "Sent %{when"
+only_en: "%{x}"
+unterminated: "a %{x %{y} b"
+typo: "a %{x} %{y} b"
+`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	es, err := katolomb.NewYAMLTranslator([]byte(`
+greeting: "Hola %{nombre}, %{title}"
+inbox: "Tienes %{count} mensajes en %{folder|tu buzón}"
+broken: "Enviado %{when}"
+only_es: "%{} y %{|x}"
+unterminated: "a %{x} %{y} b"
+typo: "a %{x %{y} b %{z}"
+`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	_, err = katolomb.CheckPlaceholders("en", map[string]katolomb.EnumerableTranslator{
+		"en.yml": en.(katolomb.EnumerableTranslator),
+		"es.yml": es.(katolomb.EnumerableTranslator),
+	})
+	if err == nil {
+		t.Errorf("expected CheckPlaceholders to return error when the reference locale has no translator")
+	}
+	issues, err := katolomb.CheckPlaceholders("en.yml", map[string]katolomb.EnumerableTranslator{
+		"en.yml": en.(katolomb.EnumerableTranslator),
+		"es.yml": es.(katolomb.EnumerableTranslator),
+	})
+	if err != nil {
+		t.Fatalf("expected CheckPlaceholders not to return error, got %v", err)
+	}
+	expected := []string{
+		`en.yml: "broken": malformed declaration "%{when"`,
+		`es.yml: "broken": property "when" is not declared in en.yml`,
+		`es.yml: "greeting": property "name" declared in en.yml is missing`,
+		`es.yml: "greeting": property "nombre" is not declared in en.yml`,
+		`es.yml: "greeting": property "title" has a default value in only one of this locale and en.yml`,
+		`es.yml: "inbox": property "folder" is not declared in en.yml`,
+		`es.yml: "only_es": malformed declaration "%{|x}"`,
+		`es.yml: "only_es": malformed declaration "%{}"`,
+		`es.yml: "typo": property "z" is not declared in en.yml`,
+		`es.yml: "typo": malformed declaration "%{x"`,
+		`en.yml: "unterminated": malformed declaration "%{x"`,
+	}
+	result := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, issue.String())
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected CheckPlaceholders to return issues\n%v\ngot\n%v", expected, result)
+	}
+	if issues[0].Kind != katolomb.PlaceholderMalformed || issues[0].Kind.String() != "malformed" {
+		t.Errorf("expected first issue to be of kind malformed, got %v", issues[0].Kind)
+	}
+}
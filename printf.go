@@ -0,0 +1,194 @@
+package katolomb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var printfRegexp = regexp.MustCompile(`%(?:%|(?:(?P<position>[1-9][0-9]*)\$)?(?P<flags>[-+ #0]*)(?P<width>[0-9]+)?(?P<precision>\.[0-9]+)?(?P<verb>[sdiufFeEgGxXoc@]))`)
+
+type printfInterpolator struct {
+	names []string
+}
+
+type printfDeclaration struct {
+	start, end int
+	escape     bool
+	name       string
+	format     string
+	verb       byte
+}
+
+// NewPrintfInterpolator returns an Interpolator that can detect and
+// interpolate printf-style placeholders, as found in Android and iOS string
+// resources, with the following format:
+//   %<position>$<flags><width>.<precision><verb>
+// where:
+//   * <position> is the 1-based position of the argument to interpolate,
+//   which is mapped to the property with that number as name ("1", "2", ...).
+//   * <flags>, <width> and .<precision> are optional and have the same
+//   meaning as in the fmt package.
+//   * <verb> is one of s, @ (strings), d, i, u, x, X, o, c (integers) and f, F,
+//   e, E, g, G (floating-point numbers).
+//   * the <position>$ part is optional; placeholders without it take
+//   consecutive positions starting at 1.
+// The %% sequence is interpolated as a single %.
+//
+// Property values for integer and floating-point verbs are parsed as such and
+// formatted with the verb, and values that cannot be parsed produce an
+// *InterpolationError.
+func NewPrintfInterpolator() Interpolator {
+	return NewPrintfInterpolatorWithNames(nil)
+}
+
+// NewPrintfInterpolatorWithNames returns an Interpolator like the one returned
+// by NewPrintfInterpolator that maps the argument in position n to the
+// property named by the nth element of names, falling back to the number
+// itself for positions beyond the list.
+func NewPrintfInterpolatorWithNames(names []string) Interpolator {
+	return &printfInterpolator{names: names}
+}
+
+// Interpolate takes a string and a TranslationProperties and returns the string
+// with any printf-style placeholders replaced with the right properties in the
+// TranslationProperties parameter formatted with the placeholder's verb. If a
+// property is not available or cannot be formatted, an *InterpolationError is
+// returned.
+func (pi *printfInterpolator) Interpolate(text string, properties TranslationProperties) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, d := range findPrintfDeclarations(text, pi.names) {
+		b.WriteString(text[last:d.start])
+		last = d.end
+		if d.escape {
+			b.WriteByte('%')
+			continue
+		}
+		value, err := properties.Property(d.name)
+		if err != nil {
+			return "", &InterpolationError{Property: d.name, Text: text, Err: err}
+		}
+		formatted, err := d.formatValue(value)
+		if err != nil {
+			return "", &InterpolationError{Property: d.name, Text: text, Err: err}
+		}
+		b.WriteString(formatted)
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// PrintfToNamed converts the printf-style placeholders in text, as detected
+// by the Interpolator returned by NewPrintfInterpolatorWithNames with the
+// given names, into %{<property name>} declarations, as detected by the
+// Interpolator returned by NewInterpolator. The %% sequence is converted into
+// a single %. Flags, width and precision are dropped in the conversion.
+func PrintfToNamed(text string, names []string) string {
+	var b strings.Builder
+	last := 0
+	for _, d := range findPrintfDeclarations(text, names) {
+		b.WriteString(text[last:d.start])
+		last = d.end
+		if d.escape {
+			b.WriteByte('%')
+			continue
+		}
+		b.WriteString("%{" + d.name + "}")
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// NamedToPrintf converts the %{<property name>|<default value>} declarations
+// in text, as detected by the Interpolator returned by NewInterpolator, into
+// positional %<position>$s placeholders, escaping any other % in the text as
+// %%. Properties are given the position of their name in names, and those
+// not in names are appended to it. It returns the converted text along with
+// the resulting list of names, suitable for NewPrintfInterpolatorWithNames.
+// Default values are dropped in the conversion.
+func NamedToPrintf(text string, names []string) (string, []string) {
+	positions := make(map[string]int, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, ok := positions[name]; !ok {
+			positions[name] = len(result) + 1
+		}
+		result = append(result, name)
+	}
+	var b strings.Builder
+	msg := defaultDeclarationParser.compileMessage(text)
+	for _, segment := range msg.segments {
+		if !segment.placeholder {
+			b.WriteString(strings.Replace(segment.literal, "%", "%%", -1))
+			continue
+		}
+		position, ok := positions[segment.property]
+		if !ok {
+			result = append(result, segment.property)
+			position = len(result)
+			positions[segment.property] = position
+		}
+		b.WriteString("%" + strconv.Itoa(position) + "$s")
+	}
+	return b.String(), result
+}
+
+func findPrintfDeclarations(text string, names []string) []printfDeclaration {
+	matches := printfRegexp.FindAllStringSubmatchIndex(text, -1)
+	declarations := make([]printfDeclaration, 0, len(matches))
+	next := 1
+	for _, m := range matches {
+		d := printfDeclaration{start: m[0], end: m[1]}
+		if text[m[0]:m[1]] == "%%" {
+			d.escape = true
+			declarations = append(declarations, d)
+			continue
+		}
+		position := next
+		if m[2] >= 0 {
+			position, _ = strconv.Atoi(text[m[2]:m[3]])
+		} else {
+			next++
+		}
+		d.name = strconv.Itoa(position)
+		if position <= len(names) {
+			d.name = names[position-1]
+		}
+		d.verb = text[m[10]]
+		d.format = "%" + submatch(text, m, 2) + submatch(text, m, 3) + submatch(text, m, 4)
+		declarations = append(declarations, d)
+	}
+	return declarations
+}
+
+func submatch(text string, m []int, group int) string {
+	if m[2*group] < 0 {
+		return ""
+	}
+	return text[m[2*group]:m[2*group+1]]
+}
+
+func (d *printfDeclaration) formatValue(value string) (string, error) {
+	switch d.verb {
+	case 's', '@':
+		return fmt.Sprintf(d.format+"s", value), nil
+	case 'd', 'i', 'u', 'x', 'X', 'o', 'c':
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("formatting %v as integer: %w", strconv.Quote(value), err)
+		}
+		verb := d.verb
+		if verb == 'i' || verb == 'u' {
+			verb = 'd'
+		}
+		return fmt.Sprintf(d.format+string(verb), n), nil
+	default:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", fmt.Errorf("formatting %v as floating-point number: %w", strconv.Quote(value), err)
+		}
+		return fmt.Sprintf(d.format+string(d.verb), f), nil
+	}
+}
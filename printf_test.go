@@ -0,0 +1,99 @@
+package katolomb_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestNewPrintfInterpolator(t *testing.T) {
+	props := katolomb.NewTranslationProperties(map[string]string{
+		"1":     "Alan",
+		"2":     "3",
+		"3":     "2.5",
+		"name":  "Frida",
+		"count": "42",
+	})
+	testCases := []struct {
+		interpolator katolomb.Interpolator
+		text         string
+		result       string
+		errNotNil    bool
+		description  string
+	}{
+		{katolomb.NewPrintfInterpolator(), "no placeholders", "no placeholders", false, "no placeholders"},
+		{katolomb.NewPrintfInterpolator(), "%1$s has %2$d cats", "Alan has 3 cats", false, "positional placeholders"},
+		{katolomb.NewPrintfInterpolator(), "%2$d cats belong to %1$@", "3 cats belong to Alan", false, "reordered positional placeholders"},
+		{katolomb.NewPrintfInterpolator(), "%s has %d cats", "Alan has 3 cats", false, "non-positional placeholders"},
+		{katolomb.NewPrintfInterpolator(), "%3$.2f km, 100%%", "2.50 km, 100%", false, "precision and an escaped percent"},
+		{katolomb.NewPrintfInterpolator(), "%2$03d|%2$-3d|%2$x", "003|3  |3", false, "flags and width"},
+		{katolomb.NewPrintfInterpolator(), "%1$d cats", "", true, "a value that is not an integer"},
+		{katolomb.NewPrintfInterpolator(), "%4$s", "", true, "a missing property"},
+		{katolomb.NewPrintfInterpolatorWithNames([]string{"name", "count"}), "%1$s has %2$i cats and %3$s", "Frida has 42 cats and 2.5", false, "named positions"},
+	}
+	for _, tc := range testCases {
+		result, err := tc.interpolator.Interpolate(tc.text, props)
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			t.Errorf("expected Interpolate to return error (%v) for a text with %v, got %v", tc.errNotNil, tc.description, err)
+		}
+		var ie *katolomb.InterpolationError
+		if errNotNil && !errors.As(err, &ie) {
+			t.Errorf("expected Interpolate to return an *InterpolationError for a text with %v, got %v", tc.description, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Interpolate to return %v for a text with %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestPrintfToNamed(t *testing.T) {
+	testCases := []struct {
+		text   string
+		names  []string
+		result string
+	}{
+		{"%1$s has %2$d cats, 100%%", nil, "%{1} has %{2} cats, 100%"},
+		{"%s has %.1f cats", []string{"name"}, "%{name} has %{2} cats"},
+		{"%2$s and %1$@", []string{"a", "b"}, "%{b} and %{a}"},
+	}
+	for _, tc := range testCases {
+		if result := katolomb.PrintfToNamed(tc.text, tc.names); result != tc.result {
+			t.Errorf("expected PrintfToNamed to return %v for %v, got %v", strconv.Quote(tc.result), strconv.Quote(tc.text), strconv.Quote(result))
+		}
+	}
+}
+
+func TestNamedToPrintf(t *testing.T) {
+	testCases := []struct {
+		text        string
+		names       []string
+		result      string
+		resultNames []string
+	}{
+		{"%{name} has %{count} cats, 100%", nil, "%1$s has %2$s cats, 100%%", []string{"name", "count"}},
+		{"%{name|Frida} and %{name}", nil, "%1$s and %1$s", []string{"name"}},
+		{"%{count} cats of %{name}", []string{"name"}, "%2$s cats of %1$s", []string{"name", "count"}},
+	}
+	for _, tc := range testCases {
+		result, names := katolomb.NamedToPrintf(tc.text, tc.names)
+		if result != tc.result {
+			t.Errorf("expected NamedToPrintf to return %v for %v, got %v", strconv.Quote(tc.result), strconv.Quote(tc.text), strconv.Quote(result))
+		}
+		if !reflect.DeepEqual(names, tc.resultNames) {
+			t.Errorf("expected NamedToPrintf to return names %v for %v, got %v", tc.resultNames, strconv.Quote(tc.text), names)
+		}
+		props := katolomb.NewTranslationProperties(map[string]string{"name": "Frida", "count": "3"})
+		named, err := katolomb.NewInterpolator().Interpolate(tc.text, props)
+		if err != nil {
+			t.Fatal(err)
+		}
+		printf, err := katolomb.NewPrintfInterpolatorWithNames(names).Interpolate(result, props)
+		if err != nil || printf != named {
+			t.Errorf("expected converted text %v to interpolate to %v, got %v, %v", strconv.Quote(result), strconv.Quote(named), strconv.Quote(printf), err)
+		}
+	}
+}
@@ -1,7 +1,5 @@
 package katolomb
 
-import "fmt"
-
 // TranslationProperties is the interface that wraps the Property method used by
 // interpolators to obtain the values the text requires.
 //
@@ -17,12 +15,13 @@ type TranslationPropertiesFunc func(string) (string, error)
 
 // NewTranslationProperties wraps a map of strings to strings and returns a
 // TranslationProperties providing access to the elements of the map with its
-// Property method
+// Property method. Properties not present in the map produce an error wrapping
+// ErrPropertyMissing.
 func NewTranslationProperties(ps map[string]string) TranslationProperties {
 	return TranslationPropertiesFunc(func(p string) (string, error) {
 		v, ok := ps[p]
 		if !ok {
-			return "", fmt.Errorf("property not available")
+			return "", ErrPropertyMissing
 		}
 		return v, nil
 	})
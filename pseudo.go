@@ -0,0 +1,123 @@
+package katolomb
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// PseudoOptions holds the configuration of a pseudo-localization translator.
+//
+// Expansion is the fraction of the text's length to add as padding, to
+// simulate languages with longer texts (e.g. 0.3 adds 30%), up to
+// MaxPseudoExpansion. Brackets wraps
+// texts with "[" and "]" markers, so truncated texts can be spotted. Mirror
+// wraps texts with right-to-left override characters, to simulate
+// right-to-left languages.
+type PseudoOptions struct {
+	Expansion float64
+	Brackets  bool
+	Mirror    bool
+}
+
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ',
+	'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ',
+	'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û',
+	'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ',
+	'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ',
+	'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û',
+	'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// MaxPseudoExpansion is the largest Expansion applied by pseudo-localization;
+// larger values are treated as it.
+const MaxPseudoExpansion = 10.0
+
+const (
+	pseudoPadding       = "~"
+	rightToLeftOverride = "\u202e"
+	popDirectional      = "\u202c"
+)
+
+// DefaultPseudoOptions returns the PseudoOptions used by NewPseudoTranslator:
+// a 30% expansion with bracket markers and no mirroring.
+func DefaultPseudoOptions() PseudoOptions {
+	return PseudoOptions{Expansion: 0.3, Brackets: true}
+}
+
+// NewPseudoTranslator takes a Translator and returns a new Translator that
+// wraps the Translator parameter to turn its translations into pseudo-locale
+// text with the DefaultPseudoOptions. See Pseudolocalize for details.
+func NewPseudoTranslator(translator Translator) Translator {
+	return NewPseudoTranslatorWithOptions(translator, DefaultPseudoOptions())
+}
+
+// NewPseudoTranslatorWithOptions takes a Translator and PseudoOptions and
+// returns a new Translator that wraps the Translator parameter to turn its
+// translations into pseudo-locale text with the given options. Errors from
+// the Translator parameter are returned right away.
+//
+// Interpolation declarations are left untouched, so the result can be wrapped
+// with NewInterpolatedTranslator. See Pseudolocalize for details.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator).
+func NewPseudoTranslatorWithOptions(translator Translator, opts PseudoOptions) Translator {
+	ct := AsContextTranslator(translator)
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		t, err := ct.TranslateContext(ctx, key, props)
+		if err != nil {
+			return "", err
+		}
+		return Pseudolocalize(t, opts), nil
+	})
+}
+
+// Pseudolocalize returns the text turned into pseudo-locale text: ASCII
+// letters are replaced with accented versions, padding is added according to
+// the options' Expansion, and the result is wrapped with bracket markers and
+// right-to-left overrides as configured in the options.
+//
+// Interpolation declarations in the format of the Interpolator returned by
+// NewInterpolator (%{<property name>|<default value>}) are left untouched and
+// are not counted for the expansion.
+func Pseudolocalize(text string, opts PseudoOptions) string {
+	var b strings.Builder
+	b.Grow(2 * len(text))
+	if opts.Brackets {
+		b.WriteString("[")
+	}
+	if opts.Mirror {
+		b.WriteString(rightToLeftOverride)
+	}
+	length := 0
+	msg := defaultDeclarationParser.compileMessage(text)
+	for _, segment := range msg.segments {
+		if segment.placeholder {
+			b.WriteString(segment.literal)
+			continue
+		}
+		for _, r := range segment.literal {
+			if a, ok := pseudoAccents[r]; ok {
+				r = a
+			}
+			b.WriteRune(r)
+		}
+		length += utf8.RuneCountInString(segment.literal)
+	}
+	if opts.Expansion > 0 {
+		expansion := math.Min(opts.Expansion, MaxPseudoExpansion)
+		b.WriteString(strings.Repeat(pseudoPadding, int(math.Ceil(float64(length)*expansion))))
+	}
+	if opts.Mirror {
+		b.WriteString(popDirectional)
+	}
+	if opts.Brackets {
+		b.WriteString("]")
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package katolomb_test
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestPseudolocalize(t *testing.T) {
+	testCases := []struct {
+		text        string
+		opts        katolomb.PseudoOptions
+		result      string
+		description string
+	}{
+		{"Hello", katolomb.PseudoOptions{}, "Ĥéļļö", "no options"},
+		{"Hello", katolomb.DefaultPseudoOptions(), "[Ĥéļļö~~]", "the default options"},
+		{"Hello", katolomb.PseudoOptions{Expansion: 1}, "Ĥéļļö~~~~~", "a full expansion"},
+		{"Hello", katolomb.PseudoOptions{Mirror: true, Brackets: true}, "[\u202eĤéļļö\u202c]", "mirroring"},
+		{"Hi %{name|Kató}, 100%!", katolomb.PseudoOptions{Brackets: true, Expansion: 0.5}, "[Ĥî %{name|Kató}, 100%!~~~~~]", "interpolation declarations"},
+		{"", katolomb.DefaultPseudoOptions(), "[]", "an empty text"},
+		{"Hello", katolomb.PseudoOptions{Expansion: 1e300}, "Ĥéļļö" + strings.Repeat("~", 50), "a huge expansion"},
+		{"Hello", katolomb.PseudoOptions{Expansion: math.Inf(1)}, "Ĥéļļö" + strings.Repeat("~", 50), "an infinite expansion"},
+		{"Hello", katolomb.PseudoOptions{Expansion: math.NaN()}, "Ĥéļļö", "a NaN expansion"},
+	}
+	for _, tc := range testCases {
+		if result := katolomb.Pseudolocalize(tc.text, tc.opts); result != tc.result {
+			t.Errorf("expected Pseudolocalize to return %v with %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestNewPseudoTranslator(t *testing.T) {
+	errTranslator := katolomb.TranslatorFunc(func(k string, p katolomb.TranslationProperties) (string, error) {
+		return "", fmt.Errorf("some error")
+	})
+	successfulTranslator := katolomb.TranslatorFunc(func(key string, p katolomb.TranslationProperties) (string, error) {
+		return "Hello %{name}", nil
+	})
+	props := katolomb.NewTranslationProperties(map[string]string{"name": "Kató"})
+	translator := katolomb.NewInterpolatedTranslator(katolomb.NewPseudoTranslator(successfulTranslator), katolomb.NewInterpolator())
+	result, err := translator.Translate("greeting", props)
+	if err != nil {
+		t.Errorf("expected Translate not to return error when the wrapped translator is successful, got %v", err)
+	}
+	if expected := "[Ĥéļļö Kató~~]"; result != expected {
+		t.Errorf("expected Translate to return %v when the wrapped translator is successful, got %v", strconv.Quote(expected), strconv.Quote(result))
+	}
+	result, err = katolomb.NewPseudoTranslator(errTranslator).Translate("greeting", props)
+	if err == nil || result != "" {
+		t.Errorf("expected Translate to return an error when the wrapped translator returns an error")
+	}
+}
@@ -0,0 +1,137 @@
+package katolomb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxReferenceDepth is the maximum number of nested key references
+// followed by the Translator returned by NewReferenceTranslator.
+const DefaultMaxReferenceDepth = 10
+
+// ReferenceOptions holds the configuration of a reference translator.
+//
+// Separator is the key separator admitted in references with the @:<key>
+// format, with an empty separator meaning those references are made of a
+// single key segment. MaxDepth is the maximum number of nested references
+// followed.
+type ReferenceOptions struct {
+	Separator string
+	MaxDepth  int
+}
+
+var referenceRegexp = newReferenceRegexp(".")
+
+// DefaultReferenceOptions returns the ReferenceOptions used by
+// NewReferenceTranslator: the "." separator and DefaultMaxReferenceDepth.
+func DefaultReferenceOptions() ReferenceOptions {
+	return ReferenceOptions{Separator: ".", MaxDepth: DefaultMaxReferenceDepth}
+}
+
+// NewReferenceTranslator takes a Translator and returns a new Translator that
+// wraps the Translator parameter to resolve references to other keys in its
+// translations, following at most DefaultMaxReferenceDepth nested references.
+//
+// See NewReferenceTranslatorWithOptions for the reference declaration format.
+func NewReferenceTranslator(translator Translator) Translator {
+	return NewReferenceTranslatorWithOptions(translator, DefaultReferenceOptions())
+}
+
+// NewReferenceTranslatorWithMaxDepth takes a Translator and a maximum depth
+// and returns a new Translator like the one returned by
+// NewReferenceTranslator that follows at most maxDepth nested references.
+func NewReferenceTranslatorWithMaxDepth(translator Translator, maxDepth int) Translator {
+	opts := DefaultReferenceOptions()
+	opts.MaxDepth = maxDepth
+	return NewReferenceTranslatorWithOptions(translator, opts)
+}
+
+// NewReferenceTranslatorWithOptions takes a Translator and ReferenceOptions
+// and returns a new Translator that wraps the Translator parameter to resolve
+// references to other keys in its translations.
+//
+// References can be declared with any of the following formats:
+//   @:<key>
+//   $t(<key>)
+// where <key> is the key of the referenced translation. The first format only
+// admits keys made of segments of letters, digits, '_' and '-' characters
+// joined by the options' separator (not ending with it), while the second one
+// admits any key without parentheses.
+//
+// References are replaced at lookup time with the referenced key's
+// translation, obtained with the same properties and with its own references
+// resolved in turn. Referenced translations are inlined verbatim, so when the
+// result is wrapped with NewInterpolatedTranslator, their interpolations are
+// evaluated with the caller's properties.
+//
+// Translating a key whose translation references itself, directly or through
+// other translations, returns an error wrapping ErrReferenceCycle, and
+// following more than the options' MaxDepth nested references returns an
+// error wrapping ErrReferenceDepth. Errors translating referenced keys are
+// returned wrapped.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator) for every referenced key.
+func NewReferenceTranslatorWithOptions(translator Translator, opts ReferenceOptions) Translator {
+	re := referenceRegexp
+	if opts.Separator != "." {
+		re = newReferenceRegexp(opts.Separator)
+	}
+	ct := AsContextTranslator(translator)
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		return resolveReferences(ctx, ct, re, key, props, nil, opts.MaxDepth)
+	})
+}
+
+func newReferenceRegexp(separator string) *regexp.Regexp {
+	key := `[\w\-]+`
+	if separator != "" {
+		key += `(?:` + regexp.QuoteMeta(separator) + `[\w\-]+)*`
+	}
+	return regexp.MustCompile(`@:(?P<key>` + key + `)|\$t\((?P<tkey>[^\(\)]+)\)`)
+}
+
+func resolveReferences(ctx context.Context, translator ContextTranslator, re *regexp.Regexp, key string, props TranslationProperties, chain []string, maxDepth int) (string, error) {
+	t, err := translator.TranslateContext(ctx, key, props)
+	if err != nil {
+		return "", err
+	}
+	matches := re.FindAllStringSubmatchIndex(t, -1)
+	if len(matches) == 0 {
+		return t, nil
+	}
+	chain = append(chain, key)
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		ref := referenceKey(t, m)
+		for _, k := range chain {
+			if k == ref {
+				return "", fmt.Errorf("translating %v: referencing %v: %w", strconv.Quote(key), strconv.Quote(ref), ErrReferenceCycle)
+			}
+		}
+		if len(chain) > maxDepth {
+			return "", fmt.Errorf("translating %v: referencing %v: %w", strconv.Quote(key), strconv.Quote(ref), ErrReferenceDepth)
+		}
+		rt, err := resolveReferences(ctx, translator, re, ref, props, chain, maxDepth)
+		if err != nil {
+			return "", fmt.Errorf("translating %v: referencing %v: %w", strconv.Quote(key), strconv.Quote(ref), err)
+		}
+		b.WriteString(t[last:m[0]])
+		b.WriteString(rt)
+		last = m[1]
+	}
+	b.WriteString(t[last:])
+	return b.String(), nil
+}
+
+func referenceKey(text string, match []int) string {
+	if match[2] >= 0 {
+		return text[match[2]:match[3]]
+	}
+	return text[match[4]:match[5]]
+}
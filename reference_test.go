@@ -0,0 +1,102 @@
+package katolomb_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestNewReferenceTranslator(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`---
+common:
+  save: Save
+  cancel: Cancel
+  name: "%{name}"
+buttons:
+  save: "@:common.save"
+  save_or_cancel: "$t(common.save) or @:common.cancel."
+  greet: "Hello @:common.name!"
+  nested: "@:buttons.save now"
+  missing: "@:common.delete"
+cycles:
+  self: "@:cycles.self"
+  a: "$t(cycles.b)"
+  b: "@:cycles.a"
+deep:
+  a: "@:deep.b"
+  b: "@:deep.c"
+  c: "@:deep.d"
+  d: "end"`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	props := katolomb.NewTranslationProperties(map[string]string{"name": "Kató"})
+	testCases := []struct {
+		translator  katolomb.Translator
+		key         string
+		result      string
+		errTarget   error
+		errNotNil   bool
+		description string
+	}{
+		{katolomb.NewReferenceTranslator(yt), "common.save", "Save", nil, false, "translating a key without references"},
+		{katolomb.NewReferenceTranslator(yt), "buttons.save", "Save", nil, false, "translating a key with a @: reference"},
+		{katolomb.NewReferenceTranslator(yt), "buttons.save_or_cancel", "Save or Cancel.", nil, false, "translating a key with references in both formats"},
+		{katolomb.NewReferenceTranslator(yt), "buttons.nested", "Save now", nil, false, "translating a key with nested references"},
+		{katolomb.NewInterpolatedTranslator(katolomb.NewReferenceTranslator(yt), katolomb.NewInterpolator()), "buttons.greet", "Hello Kató!", nil, false, "interpolating a referenced translation with the caller's properties"},
+		{katolomb.NewReferenceTranslator(yt), "buttons.missing", "", katolomb.ErrKeyNotFound, true, "translating a key referencing a missing key"},
+		{katolomb.NewReferenceTranslator(yt), "cycles.self", "", katolomb.ErrReferenceCycle, true, "translating a key referencing itself"},
+		{katolomb.NewReferenceTranslator(yt), "cycles.a", "", katolomb.ErrReferenceCycle, true, "translating a key in a reference cycle"},
+		{katolomb.NewReferenceTranslatorWithMaxDepth(yt, 3), "deep.a", "end", nil, false, "following as many nested references as allowed"},
+		{katolomb.NewReferenceTranslatorWithMaxDepth(yt, 2), "deep.a", "", katolomb.ErrReferenceDepth, true, "following more nested references than allowed"},
+	}
+	for _, tc := range testCases {
+		result, err := tc.translator.Translate(tc.key, props)
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			if errNotNil {
+				t.Errorf("expected Translate not to return error when %v, got %v", tc.description, err)
+			} else {
+				t.Errorf("expected Translate to return error when %v", tc.description)
+			}
+		}
+		if tc.errTarget != nil && !errors.Is(err, tc.errTarget) {
+			t.Errorf("expected Translate to return an error matching %v when %v, got %v", tc.errTarget, tc.description, err)
+		}
+		if result != tc.result {
+			t.Errorf("expected Translate to return %v when %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestNewReferenceTranslatorWithOptions(t *testing.T) {
+	mt := katolomb.NewMemoryTranslator("/")
+	mt.Replace(map[string]string{
+		"common/save":    "Save",
+		"common/cancel":  "Cancel",
+		"buttons/save":   "@:common/save",
+		"buttons/both":   "@:common/save or $t(common/cancel).",
+		"buttons/dotted": "@:common.save",
+	})
+	props := katolomb.NewTranslationProperties(nil)
+	opts := katolomb.DefaultReferenceOptions()
+	opts.Separator = "/"
+	translator := katolomb.NewReferenceTranslatorWithOptions(mt, opts)
+	testCases := []struct {
+		key       string
+		result    string
+		errTarget error
+	}{
+		{"buttons/save", "Save", nil},
+		{"buttons/both", "Save or Cancel.", nil},
+		{"buttons/dotted", "", katolomb.ErrIncompletePath},
+	}
+	for _, tc := range testCases {
+		result, err := translator.Translate(tc.key, props)
+		if result != tc.result || !errors.Is(err, tc.errTarget) {
+			t.Errorf("expected Translate to return %q and %v for %v with the \"/\" separator, got %q and %v", tc.result, tc.errTarget, tc.key, result, err)
+		}
+	}
+}
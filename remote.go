@@ -0,0 +1,201 @@
+package katolomb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RemoteOptions holds the configuration of a remote translator.
+//
+// Client is the http.Client used to download bundles, TTL the time a
+// downloaded bundle is served before revalidating it, and Separator the
+// separator used to join the keys of nested bundles.
+type RemoteOptions struct {
+	Client    *http.Client
+	TTL       time.Duration
+	Separator string
+}
+
+// RemoteTranslator is an EnumerableTranslator that serves the translations of
+// a bundle downloaded from an HTTP endpoint, such as one served by a handler
+// returned by NewBundleHandler. It is safe for concurrent use, and must be
+// closed with its Close method to stop revalidating the bundle.
+type RemoteTranslator struct {
+	url          string
+	opts         RemoteOptions
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	mu           sync.RWMutex
+	translations map[string]string
+	etag         string
+	expires      time.Time
+	closed       bool
+	refreshing   int32
+}
+
+// DefaultRemoteTimeout is the timeout of the http.Client in the
+// RemoteOptions returned by DefaultRemoteOptions.
+const DefaultRemoteTimeout = 10 * time.Second
+
+// DefaultRemoteOptions returns the RemoteOptions using an http.Client with
+// a timeout of DefaultRemoteTimeout, a TTL of 5 minutes and the "."
+// separator.
+func DefaultRemoteOptions() RemoteOptions {
+	return RemoteOptions{
+		Client:    &http.Client{Timeout: DefaultRemoteTimeout},
+		TTL:       5 * time.Minute,
+		Separator: ".",
+	}
+}
+
+// NewRemoteTranslator takes a context, the URL of a bundle and RemoteOptions
+// and returns a RemoteTranslator with the translations of the bundle, or an
+// error if it cannot be downloaded.
+//
+// Bundles are JSON objects, either flat with keys as attributes and
+// translations as values, or nested, with the keys of their translations made
+// by joining the attributes on their route with the options' separator.
+//
+// Once the TTL of the bundle expires, the next translation starts revalidating
+// it in the background with a conditional request using its ETag, so
+// translations never wait for the endpoint and keep serving the bundle in
+// memory meanwhile. If the bundle cannot be downloaded, its translations are
+// kept and served for another TTL before trying again. A nil Client in the
+// options is replaced with the one in DefaultRemoteOptions.
+func NewRemoteTranslator(ctx context.Context, url string, opts RemoteOptions) (*RemoteTranslator, error) {
+	if opts.Client == nil {
+		opts.Client = DefaultRemoteOptions().Client
+	}
+	rt := &RemoteTranslator{url: url, opts: opts}
+	err := rt.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rt.ctx, rt.cancel = context.WithCancel(context.Background())
+	return rt, nil
+}
+
+// Close stops the revalidation of the RemoteTranslator's bundle, cancelling
+// any revalidation in progress and waiting for it to finish. Translations in
+// memory are still served after closing, but never revalidated again.
+func (rt *RemoteTranslator) Close() error {
+	rt.mu.Lock()
+	rt.closed = true
+	rt.mu.Unlock()
+	rt.cancel()
+	rt.wg.Wait()
+	return nil
+}
+
+// Refresh revalidates the RemoteTranslator's bundle, replacing the
+// translations in memory if it changed. If it cannot be downloaded, an error
+// is returned and the translations in memory are kept. Unless the error is
+// caused by the context being done, the bundle is not revalidated again by
+// translations until another TTL passes.
+func (rt *RemoteTranslator) Refresh(ctx context.Context) error {
+	rt.mu.RLock()
+	etag := rt.etag
+	rt.mu.RUnlock()
+	translations, etag, err := rt.fetch(ctx, etag)
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.expires = time.Now().Add(rt.opts.TTL)
+	if err != nil {
+		return err
+	}
+	if translations != nil {
+		rt.translations = translations
+		rt.etag = etag
+	}
+	return nil
+}
+
+func (rt *RemoteTranslator) fetch(ctx context.Context, etag string) (map[string]string, string, error) {
+	req, err := http.NewRequest(http.MethodGet, rt.url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching bundle from %v: %v", rt.url, err)
+	}
+	req = req.WithContext(ctx)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := rt.opts.Client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching bundle from %v: %v", rt.url, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if etag != "" {
+			return nil, etag, nil
+		}
+		fallthrough
+	default:
+		return nil, "", fmt.Errorf("fetching bundle from %v: unexpected status %v", rt.url, resp.Status)
+	}
+	var bundle map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&bundle)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching bundle from %v: %v", rt.url, err)
+	}
+	translations := make(map[string]string)
+	flattenBundle(translations, "", bundle, rt.opts.Separator)
+	return translations, resp.Header.Get("ETag"), nil
+}
+
+func flattenBundle(translations map[string]string, prefix string, bundle map[string]interface{}, separator string) {
+	for k, v := range bundle {
+		switch v := v.(type) {
+		case string:
+			translations[prefix+k] = v
+		case map[string]interface{}:
+			flattenBundle(translations, prefix+k+separator, v, separator)
+		}
+	}
+}
+
+// Translate returns the translation in memory for the key, or an error
+// wrapping ErrKeyNotFound if there is none. If the TTL of the bundle expired
+// and it is not being revalidated already, its revalidation is started in the
+// background, unless the RemoteTranslator is closed.
+func (rt *RemoteTranslator) Translate(key string, props TranslationProperties) (string, error) {
+	rt.mu.RLock()
+	t, ok := rt.translations[key]
+	if !rt.closed && !time.Now().Before(rt.expires) && atomic.CompareAndSwapInt32(&rt.refreshing, 0, 1) {
+		rt.wg.Add(1)
+		go func() {
+			defer rt.wg.Done()
+			defer atomic.StoreInt32(&rt.refreshing, 0)
+			rt.Refresh(rt.ctx)
+		}()
+	}
+	rt.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), ErrKeyNotFound)
+	}
+	return t, nil
+}
+
+// Keys returns the sorted list of keys with a translation in memory.
+func (rt *RemoteTranslator) Keys() []string {
+	rt.mu.RLock()
+	keys := make([]string, 0, len(rt.translations))
+	for k := range rt.translations {
+		keys = append(keys, k)
+	}
+	rt.mu.RUnlock()
+	sort.Strings(keys)
+	return keys
+}
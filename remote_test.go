@@ -0,0 +1,237 @@
+package katolomb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestRemoteTranslator(t *testing.T) {
+	var requests, notModified, down int32
+	bundle := `{"hello": "Hello!", "checkout": {"title": "Checkout"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(bundle))
+	}))
+	defer server.Close()
+
+	opts := katolomb.DefaultRemoteOptions()
+	opts.TTL = 0
+	rt, err := katolomb.NewRemoteTranslator(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("expected NewRemoteTranslator not to return error, got %v", err)
+	}
+	defer rt.Close()
+	if keys := rt.Keys(); !reflect.DeepEqual(keys, []string{"checkout.title", "hello"}) {
+		t.Errorf("expected Keys to return flattened bundle keys, got %v", keys)
+	}
+	props := katolomb.NewTranslationProperties(nil)
+	testCases := []struct {
+		key      string
+		expected string
+		err      error
+	}{
+		{"hello", "Hello!", nil},
+		{"checkout.title", "Checkout", nil},
+		{"checkout", "", katolomb.ErrKeyNotFound},
+	}
+	for _, tc := range testCases {
+		result, err := rt.Translate(tc.key, props)
+		if result != tc.expected || !errors.Is(err, tc.err) {
+			t.Errorf("expected Translate to return %q and %v for %v, got %q and %v", tc.expected, tc.err, tc.key, result, err)
+		}
+	}
+	if !eventually(func() bool { return atomic.LoadInt32(&notModified) > 0 }) {
+		t.Errorf("expected translations with expired TTL to revalidate the bundle with its ETag in the background")
+	}
+	if err := rt.Refresh(context.Background()); err != nil {
+		t.Errorf("expected Refresh not to return error when the bundle is not modified, got %v", err)
+	}
+	if result, err := rt.Translate("hello", props); result != "Hello!" || err != nil {
+		t.Errorf("expected Translate to keep serving translations when the bundle is not modified, got %q and %v", result, err)
+	}
+
+	atomic.StoreInt32(&down, 1)
+	if err := rt.Refresh(context.Background()); err == nil {
+		t.Errorf("expected Refresh to return error when the endpoint is down")
+	}
+	if result, err := rt.Translate("hello", props); result != "Hello!" || err != nil {
+		t.Errorf("expected Translate to serve stale translations when the endpoint is down, got %q and %v", result, err)
+	}
+
+	if _, err := katolomb.NewRemoteTranslator(context.Background(), server.URL, opts); err == nil {
+		t.Errorf("expected NewRemoteTranslator to return error when the endpoint is down")
+	}
+}
+
+func TestRemoteTranslatorTTL(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"hello": "Hello!"}`))
+	}))
+	defer server.Close()
+
+	opts := katolomb.DefaultRemoteOptions()
+	opts.TTL = time.Hour
+	rt, err := katolomb.NewRemoteTranslator(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("expected NewRemoteTranslator not to return error, got %v", err)
+	}
+	defer rt.Close()
+	props := katolomb.NewTranslationProperties(nil)
+	for i := 0; i < 5; i++ {
+		rt.Translate("hello", props)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected translations within the TTL not to download the bundle again, got %d requests", n)
+	}
+
+	server.Close()
+	if err := rt.Refresh(context.Background()); err == nil {
+		t.Errorf("expected Refresh to return error when the endpoint is unreachable")
+	}
+	if result, err := rt.Translate("hello", props); result != "Hello!" || err != nil {
+		t.Errorf("expected Translate to serve stale translations when the endpoint is unreachable, got %q and %v", result, err)
+	}
+}
+
+func TestRemoteTranslatorHangingEndpoint(t *testing.T) {
+	var requests, hanging int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&hanging) == 1 {
+			<-release
+		}
+		w.Write([]byte(`{"hello": "Hello!"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	opts := katolomb.DefaultRemoteOptions()
+	opts.TTL = 0
+	rt, err := katolomb.NewRemoteTranslator(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("expected NewRemoteTranslator not to return error, got %v", err)
+	}
+	defer rt.Close()
+	atomic.StoreInt32(&hanging, 1)
+	props := katolomb.NewTranslationProperties(nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			if result, err := rt.Translate("hello", props); result != "Hello!" || err != nil {
+				t.Errorf("expected Translate to serve stale translations while the endpoint hangs, got %q and %v", result, err)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Translate not to wait for a hanging endpoint")
+	}
+	if !eventually(func() bool { return atomic.LoadInt32(&requests) == 2 }) {
+		t.Errorf("expected a single background revalidation while the endpoint hangs, got %d requests", atomic.LoadInt32(&requests))
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		rt.Close()
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Close to cancel the revalidation in progress")
+	}
+	atomic.StoreInt32(&hanging, 0)
+	if result, err := rt.Translate("hello", props); result != "Hello!" || err != nil {
+		t.Errorf("expected Translate to serve translations after closing, got %q and %v", result, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected translations not to revalidate the bundle after closing, got %d requests", n)
+	}
+}
+
+func TestRemoteTranslatorCancelledRefresh(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"hello": "Hello!"}`))
+	}))
+	defer server.Close()
+
+	opts := katolomb.DefaultRemoteOptions()
+	opts.TTL = 50 * time.Millisecond
+	rt, err := katolomb.NewRemoteTranslator(context.Background(), server.URL, opts)
+	if err != nil {
+		t.Fatalf("expected NewRemoteTranslator not to return error, got %v", err)
+	}
+	defer rt.Close()
+	time.Sleep(2 * opts.TTL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rt.Refresh(ctx); err == nil {
+		t.Errorf("expected Refresh to return error when its context is cancelled")
+	}
+	n := atomic.LoadInt32(&requests)
+	rt.Translate("hello", katolomb.NewTranslationProperties(nil))
+	if !eventually(func() bool { return atomic.LoadInt32(&requests) > n }) {
+		t.Errorf("expected a cancelled Refresh not to postpone the revalidation of an expired bundle")
+	}
+}
+
+// eventually reports whether the condition holds within a second, checking it
+// periodically.
+func eventually(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return condition()
+}
+
+func TestRemoteTranslatorWithBundleHandler(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"greeting": {"hello": "Hello %{name}!"}}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	bundleOpts := katolomb.DefaultBundleOptions()
+	bundleOpts.Nested = true
+	server := httptest.NewServer(katolomb.NewBundleHandler(map[string]katolomb.EnumerableTranslator{"en": yt.(katolomb.EnumerableTranslator)}, bundleOpts))
+	defer server.Close()
+
+	rt, err := katolomb.NewRemoteTranslator(context.Background(), server.URL+"/en.json", katolomb.DefaultRemoteOptions())
+	if err != nil {
+		t.Fatalf("expected NewRemoteTranslator not to return error, got %v", err)
+	}
+	defer rt.Close()
+	translator := katolomb.NewInterpolatedTranslator(rt, katolomb.NewInterpolator())
+	props := katolomb.NewTranslationProperties(map[string]string{"name": "John"})
+	if result, err := translator.Translate("greeting.hello", props); result != "Hello John!" || err != nil {
+		t.Errorf("expected interpolated remote translator to return %q, got %q and %v", "Hello John!", result, err)
+	}
+}
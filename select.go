@@ -0,0 +1,151 @@
+package katolomb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// SelectOptions holds the configuration of a select translator.
+//
+// Property is the name of the property whose value selects the translation
+// variant, and Separator the string used to append the variant to the key.
+// Fallback is the variant used when the property is not available or there
+// is no translation for its value. Values lists the known variants, which
+// along with Fallback are collapsed into their parent key when enumerating
+// keys. Subtree, when not empty, is inserted between the key and the variant
+// (e.g. "rank.ordinal.one" for an "ordinal" subtree). Variant, when not nil,
+// maps the property's value to a variant (e.g. "3" to "few").
+type SelectOptions struct {
+	Property  string
+	Separator string
+	Fallback  string
+	Values    []string
+	Subtree   string
+	Variant   func(string) (string, error)
+}
+
+type selectTranslator struct {
+	translator Translator
+	ct         ContextTranslator
+	opts       SelectOptions
+	variants   map[string]bool
+}
+
+type enumerableSelectTranslator struct {
+	*selectTranslator
+}
+
+// NewSelectTranslator takes a Translator and a property name and returns a
+// new Translator that wraps the Translator parameter to pick translation
+// variants with the value of the property, such as "male", "female" or
+// "other" for a "gender" property.
+//
+// The variants are looked up with the "." separator and the "other" fallback.
+// See NewSelectTranslatorWithOptions for details.
+func NewSelectTranslator(translator Translator, property string) Translator {
+	return NewSelectTranslatorWithOptions(translator, SelectOptions{
+		Property:  property,
+		Separator: ".",
+		Fallback:  "other",
+		Values:    []string{"male", "female", "other"},
+	})
+}
+
+// NewSelectTranslatorWithOptions takes a Translator and SelectOptions and
+// returns a new Translator that wraps the Translator parameter to pick
+// translation variants with the value of the options' Property.
+//
+// The result's Translate method will look for a translation with the key
+// followed by the separator and the property's value (e.g. "invite.female")
+// first, then with the key followed by the separator and the fallback (e.g.
+// "invite.other") and finally with the key itself, so keys without variants
+// are translated as usual. Only errors wrapping ErrKeyNotFound or
+// ErrIncompletePath move the lookup on to the next candidate; the last error
+// is returned when no candidate can be translated.
+//
+// Select translators can be stacked to select on several properties, such as
+// a grammatical gender and a plural category, with the outermost one
+// selecting the first variant in the key.
+//
+// When the Translator parameter is an EnumerableTranslator, the result is too,
+// and its Keys method lists keys with variants as a single logical key.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator).
+func NewSelectTranslatorWithOptions(translator Translator, opts SelectOptions) Translator {
+	variants := map[string]bool{opts.Fallback: true}
+	for _, v := range opts.Values {
+		variants[v] = true
+	}
+	st := &selectTranslator{translator: translator, ct: AsContextTranslator(translator), opts: opts, variants: variants}
+	if _, ok := translator.(EnumerableTranslator); ok {
+		return &enumerableSelectTranslator{st}
+	}
+	return st
+}
+
+func (st *selectTranslator) Translate(key string, props TranslationProperties) (string, error) {
+	return st.TranslateContext(context.Background(), key, props)
+}
+
+func (st *selectTranslator) TranslateContext(ctx context.Context, key string, props TranslationProperties) (string, error) {
+	prefix := key + st.opts.Separator
+	if st.opts.Subtree != "" {
+		prefix += st.opts.Subtree + st.opts.Separator
+	}
+	candidates := make([]string, 0, 3)
+	value, err := props.Property(st.opts.Property)
+	if err == nil && st.opts.Variant != nil {
+		value, err = st.opts.Variant(value)
+	}
+	if err == nil && value != "" && value != st.opts.Fallback {
+		candidates = append(candidates, prefix+value)
+	}
+	candidates = append(candidates, prefix+st.opts.Fallback, key)
+	for _, candidate := range candidates {
+		var t string
+		t, err = st.ct.TranslateContext(ctx, candidate, props)
+		if err == nil {
+			return t, nil
+		}
+		if !errors.Is(err, ErrKeyNotFound) && !errors.Is(err, ErrIncompletePath) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+// Keys returns the sorted list of keys of the wrapped EnumerableTranslator,
+// with keys of known variants replaced by their parent key.
+func (est *enumerableSelectTranslator) Keys() []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, k := range est.translator.(EnumerableTranslator).Keys() {
+		k = est.logicalKey(k)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (est *enumerableSelectTranslator) logicalKey(key string) string {
+	sep := est.opts.Separator
+	i := strings.LastIndex(key, sep)
+	if sep == "" || i < 0 || !est.variants[key[i+len(sep):]] {
+		return key
+	}
+	parent := key[:i]
+	if est.opts.Subtree == "" {
+		return parent
+	}
+	if !strings.HasSuffix(parent, sep+est.opts.Subtree) {
+		return key
+	}
+	return strings.TrimSuffix(parent, sep+est.opts.Subtree)
+}
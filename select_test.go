@@ -0,0 +1,110 @@
+package katolomb_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+const selectYAML = `---
+invite:
+  male: "%{name} invited you to his party"
+  female: "%{name} invited you to her party"
+  other: "%{name} invited you to their party"
+guests:
+  female:
+    one: "One guest (f)"
+    other: "%{count} guests (f)"
+  other:
+    one: "One guest"
+    other: "%{count} guests"
+title: "Party"`
+
+func TestNewSelectTranslator(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(selectYAML))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	gender := katolomb.NewSelectTranslator(yt, "gender")
+	pluralOpts := katolomb.SelectOptions{Property: "plural", Separator: ".", Fallback: "other", Values: []string{"one", "other"}}
+	genderAndPlural := katolomb.NewInterpolatedTranslator(
+		katolomb.NewSelectTranslator(katolomb.NewSelectTranslatorWithOptions(yt, pluralOpts), "gender"),
+		katolomb.NewInterpolator(),
+	)
+	testCases := []struct {
+		translator  katolomb.Translator
+		key         string
+		props       map[string]string
+		result      string
+		errNotNil   bool
+		description string
+	}{
+		{gender, "invite", map[string]string{"gender": "female"}, "%{name} invited you to her party", false, "selecting an available variant"},
+		{gender, "invite", map[string]string{"gender": "male"}, "%{name} invited you to his party", false, "selecting another available variant"},
+		{gender, "invite", map[string]string{"gender": "neuter"}, "%{name} invited you to their party", false, "falling back for an unavailable variant"},
+		{gender, "invite", map[string]string{}, "%{name} invited you to their party", false, "falling back when the property is not available"},
+		{gender, "title", map[string]string{"gender": "female"}, "Party", false, "translating a key without variants"},
+		{gender, "missing", map[string]string{"gender": "female"}, "", true, "translating a missing key"},
+		{genderAndPlural, "guests", map[string]string{"gender": "female", "plural": "other", "count": "3"}, "3 guests (f)", false, "selecting gender and plural variants and interpolating"},
+		{genderAndPlural, "guests", map[string]string{"gender": "male", "plural": "one"}, "One guest", false, "falling back on gender and selecting a plural variant"},
+		{genderAndPlural, "title", map[string]string{"gender": "male", "plural": "one"}, "Party", false, "translating a key without variants through composed selections"},
+	}
+	for _, tc := range testCases {
+		result, err := tc.translator.Translate(tc.key, katolomb.NewTranslationProperties(tc.props))
+		errNotNil := err != nil
+		if errNotNil != tc.errNotNil {
+			if errNotNil {
+				t.Errorf("expected Translate not to return error when %v, got %v", tc.description, err)
+			} else {
+				t.Errorf("expected Translate to return error when %v", tc.description)
+			}
+		}
+		if result != tc.result {
+			t.Errorf("expected Translate to return %v when %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestNewSelectTranslatorErrors(t *testing.T) {
+	someErr := errors.New("some error")
+	errTranslator := katolomb.TranslatorFunc(func(string, katolomb.TranslationProperties) (string, error) {
+		return "", someErr
+	})
+	calls := 0
+	_, err := katolomb.NewSelectTranslator(katolomb.TranslatorFunc(func(k string, p katolomb.TranslationProperties) (string, error) {
+		calls++
+		return errTranslator.Translate(k, p)
+	}), "gender").Translate("invite", katolomb.NewTranslationProperties(map[string]string{"gender": "female"}))
+	if !errors.Is(err, someErr) {
+		t.Errorf("expected Translate to return the wrapped translator's error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected Translate not to try other variants after an error other than a missing key, got %d calls", calls)
+	}
+}
+
+func TestSelectTranslatorKeys(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(selectYAML))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	pluralOpts := katolomb.SelectOptions{Property: "plural", Separator: ".", Fallback: "other", Values: []string{"one", "other"}}
+	translator := katolomb.NewSelectTranslator(katolomb.NewSelectTranslatorWithOptions(yt, pluralOpts), "gender")
+	et, ok := translator.(katolomb.EnumerableTranslator)
+	if !ok {
+		t.Fatalf("expected select translators wrapping an EnumerableTranslator to be an EnumerableTranslator")
+	}
+	expected := []string{"guests", "invite", "title"}
+	if keys := et.Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected Keys to return %v, got %v", expected, keys)
+	}
+	plain := katolomb.NewSelectTranslator(katolomb.TranslatorFunc(func(k string, p katolomb.TranslationProperties) (string, error) {
+		return k, nil
+	}), "gender")
+	if _, ok := plain.(katolomb.EnumerableTranslator); ok {
+		t.Errorf("expected select translators wrapping a non-enumerable Translator not to be an EnumerableTranslator")
+	}
+}
@@ -0,0 +1,124 @@
+//go:build go1.21
+
+package katolomb
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+type slogHandler struct {
+	handler    slog.Handler
+	translator Translator
+	locale     string
+}
+
+// LogValue returns the Message as a group with its key, as the "key"
+// attribute, and its properties, as the "properties" group, so that logs
+// carry the message instead of a rendered text. Translatable properties are
+// logged as they are, so Messages nested as properties are logged as groups
+// too.
+func (m Message) LogValue() slog.Value {
+	names := make([]string, 0, len(m.properties))
+	for name := range m.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	properties := make([]slog.Attr, 0, len(names))
+	for _, name := range names {
+		if value, ok := m.properties[name].(string); ok {
+			properties = append(properties, slog.String(name, value))
+			continue
+		}
+		properties = append(properties, slog.Any(name, m.properties[name]))
+	}
+	return slog.GroupValue(slog.String("key", m.key), slog.Attr{Key: "properties", Value: slog.GroupValue(properties...)})
+}
+
+// LogValue returns the error as a group with its message, as the "error"
+// attribute, and the attributes of its Message's LogValue.
+func (te *TranslatableError) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("error", te.Error())}
+	return slog.GroupValue(append(attrs, te.Message.LogValue().Group()...)...)
+}
+
+// NewSlogHandler takes a slog.Handler, a Translator and the locale of its
+// translations and returns a slog.Handler that wraps the handler parameter to
+// render the Translatable values of attributes, such as Messages and
+// TranslatableErrors, with the Translator.
+//
+// Attributes with a Translatable value are replaced with a group holding the
+// locale, as the "locale" attribute when not empty, the translation, as the
+// "text" attribute, and the attributes of the value's LogValue, such as the
+// key and properties of Messages. If the value cannot be translated, the
+// error is logged as the "text_error" attribute instead of the translation.
+//
+// Values of attributes in log records are translated with the context passed
+// to the handler, using the Translator's TranslateContext method when it is a
+// ContextTranslator (see AsContextTranslator).
+func NewSlogHandler(handler slog.Handler, translator Translator, locale string) slog.Handler {
+	return &slogHandler{handler: handler, translator: translator, locale: locale}
+}
+
+func (sh *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.handler.Enabled(ctx, level)
+}
+
+func (sh *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	rendered := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		rendered.AddAttrs(sh.render(ctx, a))
+		return true
+	})
+	return sh.handler.Handle(ctx, rendered)
+}
+
+func (sh *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rendered := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		rendered = append(rendered, sh.render(context.Background(), a))
+	}
+	return &slogHandler{handler: sh.handler.WithAttrs(rendered), translator: sh.translator, locale: sh.locale}
+}
+
+func (sh *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{handler: sh.handler.WithGroup(name), translator: sh.translator, locale: sh.locale}
+}
+
+func (sh *slogHandler) render(ctx context.Context, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		rendered := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			rendered = append(rendered, sh.render(ctx, ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(rendered...)}
+	case slog.KindAny, slog.KindLogValuer:
+	default:
+		return a
+	}
+	tr, ok := a.Value.Any().(Translatable)
+	if !ok {
+		return a
+	}
+	ct := AsContextTranslator(sh.translator)
+	translator := TranslatorFunc(func(key string, props TranslationProperties) (string, error) {
+		return ct.TranslateContext(ctx, key, props)
+	})
+	var attrs []slog.Attr
+	if sh.locale != "" {
+		attrs = append(attrs, slog.String("locale", sh.locale))
+	}
+	text, err := tr.Translate(translator)
+	if err != nil {
+		attrs = append(attrs, slog.String("text_error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.String("text", text))
+	}
+	if v := a.Value.Resolve(); v.Kind() == slog.KindGroup {
+		attrs = append(attrs, v.Group()...)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
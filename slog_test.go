@@ -0,0 +1,97 @@
+//go:build go1.21
+
+package katolomb_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+func TestMessageLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	inbox := katolomb.NewMessage("inbox").With("count", "3")
+	logger.Info("notified", "msg", katolomb.NewMessage("greeting").With("name", "John").WithTranslatable("inbox", inbox))
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON handler to log valid JSON, got %v", err)
+	}
+	expected := map[string]interface{}{
+		"key": "greeting",
+		"properties": map[string]interface{}{
+			"name":  "John",
+			"inbox": map[string]interface{}{"key": "inbox", "properties": map[string]interface{}{"count": "3"}},
+		},
+	}
+	if !reflect.DeepEqual(record["msg"], expected) {
+		t.Errorf("expected message to be logged as %v, got %v", expected, record["msg"])
+	}
+}
+
+func TestNewSlogHandler(t *testing.T) {
+	yt, err := katolomb.NewYAMLTranslator([]byte(`{"greeting": "Hola %{name}", "errors": {"payment_failed": "El pago ha fallado"}}`))
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(yt, katolomb.NewInterpolator())
+	var buf bytes.Buffer
+	logger := slog.New(katolomb.NewSlogHandler(slog.NewJSONHandler(&buf, nil), translator, "es"))
+	paymentErr := katolomb.NewTranslatableError(katolomb.NewMessage("errors.payment_failed"), "payment failed")
+	logger.With("greeting", katolomb.NewMessage("greeting").With("name", "Ana")).Info("checkout",
+		slog.Group("request", slog.Any("err", paymentErr), slog.String("id", "abc")),
+		slog.Any("missing", katolomb.NewMessage("greeting")),
+		slog.Int("attempt", 2),
+	)
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON handler to log valid JSON, got %v", err)
+	}
+	testCases := []struct {
+		attr     string
+		expected interface{}
+	}{
+		{"greeting", map[string]interface{}{"locale": "es", "text": "Hola Ana", "key": "greeting", "properties": map[string]interface{}{"name": "Ana"}}},
+		{"request", map[string]interface{}{"id": "abc", "err": map[string]interface{}{"locale": "es", "text": "El pago ha fallado", "error": "payment failed", "key": "errors.payment_failed"}}},
+		{"attempt", float64(2)},
+	}
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(record[tc.attr], tc.expected) {
+			t.Errorf("expected slog handler to log %v as %v, got %v", tc.attr, tc.expected, record[tc.attr])
+		}
+	}
+	missing, _ := record["missing"].(map[string]interface{})
+	if missing["text_error"] == nil || missing["text"] != nil || missing["key"] != "greeting" {
+		t.Errorf("expected slog handler to log the translation error of untranslatable messages, got %v", record["missing"])
+	}
+}
+
+func TestNewSlogHandlerContext(t *testing.T) {
+	var received context.Context
+	translator := katolomb.ContextTranslatorFunc(func(ctx context.Context, key string, props katolomb.TranslationProperties) (string, error) {
+		received = ctx
+		return key, nil
+	})
+	var buf bytes.Buffer
+	logger := slog.New(katolomb.NewSlogHandler(slog.NewJSONHandler(&buf, nil), translator, ""))
+	ctx := katolomb.WithLocale(context.Background(), "es")
+	logger.InfoContext(ctx, "checkout", "msg", katolomb.NewMessage("greeting"))
+	if received == nil {
+		t.Fatalf("expected slog handler to translate with the context translator")
+	}
+	if locale, _ := katolomb.LocaleFromContext(received); locale != "es" {
+		t.Errorf("expected slog handler to translate with the context of the log call")
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON handler to log valid JSON, got %v", err)
+	}
+	if msg, _ := record["msg"].(map[string]interface{}); msg["locale"] != nil {
+		t.Errorf("expected slog handler not to log a locale when not configured, got %v", msg["locale"])
+	}
+}
@@ -0,0 +1,141 @@
+package katolomb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SQLSchema describes the table a SQL translator reads translations from.
+//
+// Table is the name of the table, and LocaleColumn, KeyColumn and ValueColumn
+// the names of the columns holding the locale, key and value of each
+// translation. PluralColumn, when not empty, is the name of a nullable column
+// holding the plural category of the translation. Placeholder is the
+// parameter placeholder of the database driver (such as "?" or "$1").
+//
+// Names are inserted in queries as they are, so they must come from trusted
+// configuration and be quoted if the database requires it.
+type SQLSchema struct {
+	Table        string
+	LocaleColumn string
+	KeyColumn    string
+	ValueColumn  string
+	PluralColumn string
+	Placeholder  string
+}
+
+// SQLTranslator is an EnumerableTranslator that serves the translations of a
+// locale read from a database table through database/sql. Translations are
+// kept in memory and only read again from the database when refreshed. It is
+// safe for concurrent use.
+type SQLTranslator struct {
+	db           *sql.DB
+	locale       string
+	schema       SQLSchema
+	mu           sync.RWMutex
+	translations map[string]string
+}
+
+// DefaultSQLSchema returns a SQLSchema for a "translations" table with
+// "locale", "translation_key" and "value" columns, no plural column and the
+// "?" placeholder. The key column is not named "key", since it is a reserved
+// word in databases such as MySQL.
+func DefaultSQLSchema() SQLSchema {
+	return SQLSchema{
+		Table:        "translations",
+		LocaleColumn: "locale",
+		KeyColumn:    "translation_key",
+		ValueColumn:  "value",
+		Placeholder:  "?",
+	}
+}
+
+// NewSQLTranslator takes a context, a database, a locale and a SQLSchema and
+// returns a SQLTranslator with the translations of the locale in the table
+// described by the schema, or an error if they cannot be read.
+//
+// Translations with a plural category are served under their key followed by
+// "." and the category (e.g. "inbox.messages.one"), so the result can be
+// wrapped with NewSelectTranslatorWithOptions to pick them. Rows with a NULL
+// value are skipped, so their keys are not translated.
+func NewSQLTranslator(ctx context.Context, db *sql.DB, locale string, schema SQLSchema) (*SQLTranslator, error) {
+	st := &SQLTranslator{db: db, locale: locale, schema: schema}
+	err := st.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Refresh reads the translations of the SQLTranslator's locale from the
+// database again, replacing the ones in memory. If they cannot be read, an
+// error is returned and the translations in memory are kept.
+func (st *SQLTranslator) Refresh(ctx context.Context) error {
+	columns := []string{st.schema.KeyColumn, st.schema.ValueColumn}
+	if st.schema.PluralColumn != "" {
+		columns = append(columns, st.schema.PluralColumn)
+	}
+	query := fmt.Sprintf("SELECT %v FROM %v WHERE %v = %v", strings.Join(columns, ", "), st.schema.Table, st.schema.LocaleColumn, st.schema.Placeholder)
+	rows, err := st.db.QueryContext(ctx, query, st.locale)
+	if err != nil {
+		return fmt.Errorf("reading %v translations: %w", st.locale, err)
+	}
+	defer rows.Close()
+	translations := make(map[string]string)
+	for rows.Next() {
+		var key string
+		var value, plural sql.NullString
+		dest := []interface{}{&key, &value}
+		if st.schema.PluralColumn != "" {
+			dest = append(dest, &plural)
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return fmt.Errorf("reading %v translations: %w", st.locale, err)
+		}
+		if !value.Valid {
+			continue
+		}
+		if plural.Valid && plural.String != "" {
+			key += "." + plural.String
+		}
+		translations[key] = value.String
+	}
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("reading %v translations: %w", st.locale, err)
+	}
+	st.mu.Lock()
+	st.translations = translations
+	st.mu.Unlock()
+	return nil
+}
+
+// Translate returns the translation in memory for the key, or an error
+// wrapping ErrKeyNotFound if there is none.
+func (st *SQLTranslator) Translate(key string, props TranslationProperties) (string, error) {
+	st.mu.RLock()
+	t, ok := st.translations[key]
+	st.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), ErrKeyNotFound)
+	}
+	return t, nil
+}
+
+// Keys returns the sorted list of keys with a translation in memory.
+func (st *SQLTranslator) Keys() []string {
+	st.mu.RLock()
+	keys := make([]string, 0, len(st.translations))
+	for k := range st.translations {
+		keys = append(keys, k)
+	}
+	st.mu.RUnlock()
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,213 @@
+package katolomb_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pbanos/katolomb"
+)
+
+// fakeSQLDriver is an in-process database/sql driver serving the rows of
+// in-memory tables to the queries issued by SQLTranslator.
+type fakeSQLDriver struct {
+	mu     sync.Mutex
+	tables map[string][]map[string]interface{}
+	fail   bool
+}
+
+type fakeSQLConn struct{ driver *fakeSQLDriver }
+
+type fakeSQLStmt struct {
+	driver *fakeSQLDriver
+	query  string
+}
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var fakeSQL = &fakeSQLDriver{tables: make(map[string][]map[string]interface{})}
+
+func init() {
+	sql.Register("katolomb-fake", fakeSQL)
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) { return &fakeSQLConn{d}, nil }
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{c.driver, query}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (s *fakeSQLStmt) Close() error { return nil }
+
+func (s *fakeSQLStmt) NumInput() int { return 1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec not supported")
+}
+
+// Query serves queries with the form
+//
+//	SELECT <column>, <column> FROM <table> WHERE <column> = <placeholder>
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.mu.Lock()
+	defer s.driver.mu.Unlock()
+	if s.driver.fail {
+		return nil, errors.New("database is down")
+	}
+	var table, filter string
+	fields := strings.Fields(strings.Replace(s.query, ",", " ", -1))
+	if len(fields) < 7 || fields[0] != "SELECT" {
+		return nil, errors.New("unsupported query")
+	}
+	columns := []string{}
+	i := 1
+	for ; fields[i] != "FROM"; i++ {
+		columns = append(columns, fields[i])
+	}
+	table, filter = fields[i+1], fields[i+3]
+	rows := &fakeSQLRows{columns: columns}
+	for _, row := range s.driver.tables[table] {
+		if row[filter] != args[0] {
+			continue
+		}
+		values := make([]driver.Value, len(columns))
+		for j, c := range columns {
+			values[j] = row[c]
+		}
+		rows.rows = append(rows.rows, values)
+	}
+	return rows, nil
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.columns }
+
+func (r *fakeSQLRows) Close() error { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func setFakeTable(table string, rows []map[string]interface{}, fail bool) {
+	fakeSQL.mu.Lock()
+	defer fakeSQL.mu.Unlock()
+	fakeSQL.tables[table] = rows
+	fakeSQL.fail = fail
+}
+
+func TestSQLTranslator(t *testing.T) {
+	db, err := sql.Open("katolomb-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	setFakeTable("copy", []map[string]interface{}{
+		{"lang": "en", "k": "greeting", "v": "Hello %{name}", "plural": nil},
+		{"lang": "en", "k": "messages", "v": "One message", "plural": "one"},
+		{"lang": "en", "k": "messages", "v": "%{count} messages", "plural": "other"},
+		{"lang": "es", "k": "greeting", "v": "Hola %{name}", "plural": nil},
+		{"lang": "en", "k": "pending", "v": nil, "plural": nil},
+	}, false)
+	schema := katolomb.SQLSchema{Table: "copy", LocaleColumn: "lang", KeyColumn: "k", ValueColumn: "v", PluralColumn: "plural", Placeholder: "$1"}
+	st, err := katolomb.NewSQLTranslator(context.Background(), db, "en", schema)
+	if err != nil {
+		t.Fatalf("expected NewSQLTranslator not to return error, got %v", err)
+	}
+	translator := katolomb.NewInterpolatedTranslator(
+		katolomb.NewSelectTranslatorWithOptions(st, katolomb.SelectOptions{Property: "plural", Separator: ".", Fallback: "other", Values: []string{"one", "other"}}),
+		katolomb.NewInterpolator(),
+	)
+	testCases := []struct {
+		key       string
+		props     map[string]string
+		result    string
+		errNotNil bool
+	}{
+		{"greeting", map[string]string{"name": "Kató"}, "Hello Kató", false},
+		{"messages", map[string]string{"plural": "one"}, "One message", false},
+		{"messages", map[string]string{"plural": "other", "count": "3"}, "3 messages", false},
+		{"missing", map[string]string{}, "", true},
+	}
+	for _, tc := range testCases {
+		result, err := translator.Translate(tc.key, katolomb.NewTranslationProperties(tc.props))
+		if (err != nil) != tc.errNotNil || result != tc.result {
+			t.Errorf("expected Translate to return %q (error %v) for %v, got %q, %v", tc.result, tc.errNotNil, tc.key, result, err)
+		}
+	}
+	if _, err := st.Translate("missing", nil); !errors.Is(err, katolomb.ErrKeyNotFound) {
+		t.Errorf("expected Translate to return an error wrapping ErrKeyNotFound for a missing key, got %v", err)
+	}
+	expected := []string{"greeting", "messages.one", "messages.other"}
+	if keys := st.Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected Keys to return %v, got %v", expected, keys)
+	}
+
+	setFakeTable("copy", []map[string]interface{}{
+		{"lang": "en", "k": "greeting", "v": "Hi %{name}", "plural": nil},
+	}, false)
+	if result, _ := st.Translate("greeting", nil); result != "Hello %{name}" {
+		t.Errorf("expected Translate to serve cached translations before refreshing, got %q", result)
+	}
+	if err := st.Refresh(context.Background()); err != nil {
+		t.Fatalf("expected Refresh not to return error, got %v", err)
+	}
+	if result, _ := st.Translate("greeting", nil); result != "Hi %{name}" {
+		t.Errorf("expected Translate to serve refreshed translations, got %q", result)
+	}
+	setFakeTable("copy", nil, true)
+	if err := st.Refresh(context.Background()); err == nil {
+		t.Errorf("expected Refresh to return error when the database fails")
+	}
+	if result, _ := st.Translate("greeting", nil); result != "Hi %{name}" {
+		t.Errorf("expected Translate to keep serving translations after a failed refresh, got %q", result)
+	}
+	if _, err := katolomb.NewSQLTranslator(context.Background(), db, "en", katolomb.DefaultSQLSchema()); err == nil {
+		t.Errorf("expected NewSQLTranslator to return error when the database fails")
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := st.Refresh(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Refresh to return an error wrapping context.Canceled with a done context, got %v", err)
+	}
+}
+
+func TestSQLTranslatorWithDefaultSchema(t *testing.T) {
+	db, err := sql.Open("katolomb-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	setFakeTable("translations", []map[string]interface{}{
+		{"locale": "en", "translation_key": "greeting", "value": "Hello"},
+		{"locale": "en", "translation_key": "pending", "value": nil},
+	}, false)
+	st, err := katolomb.NewSQLTranslator(context.Background(), db, "en", katolomb.DefaultSQLSchema())
+	if err != nil {
+		t.Fatalf("expected NewSQLTranslator not to return error with the default schema, got %v", err)
+	}
+	if result, err := st.Translate("greeting", nil); result != "Hello" || err != nil {
+		t.Errorf("expected Translate to return %q with the default schema, got %q and %v", "Hello", result, err)
+	}
+	if _, err := st.Translate("pending", nil); !errors.Is(err, katolomb.ErrKeyNotFound) {
+		t.Errorf("expected Translate to return an error wrapping ErrKeyNotFound for a key with a NULL value, got %v", err)
+	}
+}
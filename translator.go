@@ -1,6 +1,8 @@
 package katolomb
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 )
@@ -29,10 +31,19 @@ type TranslatorFunc func(string, TranslationProperties) (string, error)
 // NewDefaultTranslator takes a default translation string and a Translator and
 // returns a new Translator that wraps the Translator parameter to return the
 // default translation when its Translate method returns an error.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator) and returns the default translation on errors unless
+// the context is done, in which case the error is returned.
 func NewDefaultTranslator(translation string, translator Translator) Translator {
-	return TranslatorFunc(func(key string, props TranslationProperties) (string, error) {
-		t, err := translator.Translate(key, props)
+	ct := AsContextTranslator(translator)
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		t, err := ct.TranslateContext(ctx, key, props)
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
 			return translation, nil
 		}
 		return t, nil
@@ -42,10 +53,19 @@ func NewDefaultTranslator(translation string, translator Translator) Translator
 // NewKeyAsDefaultTranslator takes a Translator and returns a new Translator
 // that wraps the Translator parameter to return the Translate method's key
 // parameter the Translator parameter's Translate method returns an error.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator) and returns the key on errors unless the context is
+// done, in which case the error is returned.
 func NewKeyAsDefaultTranslator(translator Translator) Translator {
-	return TranslatorFunc(func(key string, props TranslationProperties) (string, error) {
-		t, err := translator.Translate(key, props)
+	ct := AsContextTranslator(translator)
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		t, err := ct.TranslateContext(ctx, key, props)
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
 			return key, nil
 		}
 		return t, nil
@@ -57,15 +77,29 @@ func NewKeyAsDefaultTranslator(translator Translator) Translator {
 // provided by the Translator parameter's Translate method, interpolates it
 // using the Interpolator parameter and returns the result. If the translation
 // or interpolation returns an error, an error is returned right away.
+// Interpolation errors are returned as an *InterpolationError with its Key
+// field set to the translated key; other interpolation errors are wrapped
+// along with the key.
+//
+// The returned Translator is also a ContextTranslator, whose TranslateContext
+// method passes the context to the Translator parameter (see
+// AsContextTranslator).
 func NewInterpolatedTranslator(translator Translator, interpolator Interpolator) Translator {
-	return TranslatorFunc(func(key string, props TranslationProperties) (string, error) {
-		t, err := translator.Translate(key, props)
+	ct := AsContextTranslator(translator)
+	return ContextTranslatorFunc(func(ctx context.Context, key string, props TranslationProperties) (string, error) {
+		t, err := ct.TranslateContext(ctx, key, props)
 		if err != nil {
 			return "", err
 		}
 		t, err = interpolator.Interpolate(t, props)
 		if err != nil {
-			return "", fmt.Errorf("translating %v: %v", strconv.Quote(key), err)
+			var ie *InterpolationError
+			if errors.As(err, &ie) && ie.Key == "" {
+				keyed := *ie
+				keyed.Key = key
+				return "", &keyed
+			}
+			return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), err)
 		}
 		return t, nil
 	})
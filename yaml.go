@@ -2,19 +2,95 @@ package katolomb
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
+// YAMLNullPolicy decides how YAML null values are treated by YAML translators.
+type YAMLNullPolicy int
+
+const (
+	// YAMLNullAsMissing treats null values as missing translations.
+	YAMLNullAsMissing YAMLNullPolicy = iota
+	// YAMLNullAsEmpty treats null values as empty translations.
+	YAMLNullAsEmpty
+)
+
+// YAMLScalarPolicy decides how non-string YAML scalars (booleans and numbers)
+// are treated by YAML translators.
+type YAMLScalarPolicy int
+
+const (
+	// YAMLScalarAsText keeps the scalar's original text in the YAML document
+	// as translation, so that "1.50" stays "1.50" and "yes" stays "yes".
+	YAMLScalarAsText YAMLScalarPolicy = iota
+	// YAMLScalarAsValue uses the default formatting of the scalar's decoded
+	// value as translation, so that "1.50" becomes "1.5" and "yes" becomes
+	// "true".
+	YAMLScalarAsValue
+	// YAMLScalarAsMissing treats scalars as missing translations.
+	YAMLScalarAsMissing
+)
+
+// YAMLBlockScalarPolicy decides how multi-line string values, usually
+// declared in YAML as literal (|) or folded (>) block scalars, are treated by
+// YAML translators.
+type YAMLBlockScalarPolicy int
+
+const (
+	// YAMLBlockScalarKeep keeps multi-line values as decoded.
+	YAMLBlockScalarKeep YAMLBlockScalarPolicy = iota
+	// YAMLBlockScalarTrimTrailingNewlines removes the trailing line breaks
+	// that block scalars keep by default.
+	YAMLBlockScalarTrimTrailingNewlines
+	// YAMLBlockScalarJoinLines trims trailing line breaks and joins the
+	// remaining lines with a single space.
+	YAMLBlockScalarJoinLines
+)
+
+// YAMLOptions holds the configuration of a YAML translator.
+//
+// Separator is the string used to split keys into a tree route to a
+// translation, with an empty separator meaning keys are not split. Nulls,
+// Booleans, Numbers and BlockScalars are the policies to apply to the
+// corresponding values in the YAML. Flatten makes the translator build an
+// index from full keys to translations at load time, so that lookups do not
+// need to split keys and walk the tree of translations.
+type YAMLOptions struct {
+	Separator    string
+	Nulls        YAMLNullPolicy
+	Booleans     YAMLScalarPolicy
+	Numbers      YAMLScalarPolicy
+	BlockScalars YAMLBlockScalarPolicy
+	Flatten      bool
+}
+
 type yamlTranslator struct {
 	separator    string
 	translations yamlTranslations
+	flat         map[string]string
+	groups       map[string]bool
 }
 
 type yamlTranslations map[string]interface{}
 
+type yamlNode struct {
+	value    interface{}
+	text     string
+	children map[string]*yamlNode
+	items    []*yamlNode
+}
+
+// DefaultYAMLOptions returns the YAMLOptions used by NewYAMLTranslator: the
+// "." separator, nulls treated as missing, booleans and numbers kept with
+// their original text and multi-line values kept as decoded.
+func DefaultYAMLOptions() YAMLOptions {
+	return YAMLOptions{Separator: "."}
+}
+
 // NewYAMLTranslator returns a Translator that looks for translations in the
 // YAML passed as a  byte-slice parameter.
 //
@@ -24,8 +100,10 @@ type yamlTranslations map[string]interface{}
 //
 // The result's Translate method will use the "." string as separator to split
 // the key parameter into a tree route to a translation.
+//
+// The returned Translator is also an EnumerableTranslator.
 func NewYAMLTranslator(yml []byte) (Translator, error) {
-	return NewYAMLTranslatorWithSeparator(yml, ".")
+	return NewYAMLTranslatorWithOptions(yml, DefaultYAMLOptions())
 }
 
 // NewYAMLTranslatorWithSeparator returns a Translator that looks for
@@ -36,22 +114,58 @@ func NewYAMLTranslator(yml []byte) (Translator, error) {
 // keys
 //
 // The result's Translate method will use the given separator string to split
-// the key parameter into a tree route to a translation.
+// the key parameter into a tree route to a translation. Keys with no
+// translation produce errors wrapping ErrKeyNotFound, while keys leading to a
+// group of translations produce errors wrapping ErrIncompletePath.
+//
+// The returned Translator is also an EnumerableTranslator.
 func NewYAMLTranslatorWithSeparator(yml []byte, separator string) (Translator, error) {
-	ts := make(map[interface{}]interface{})
+	opts := DefaultYAMLOptions()
+	opts.Separator = separator
+	return NewYAMLTranslatorWithOptions(yml, opts)
+}
+
+// NewYAMLTranslatorWithOptions returns a Translator that looks for
+// translations in the YAML in the byte slice passed as parameter, configured
+// with the given YAMLOptions.
+//
+// The translation's YAML will be deserialized and its keys treated as strings,
+// keeping their original text. Lists in the YAML will be treated as maps with
+// string-formatted integers as keys. Scalar values are turned into
+// translations according to the policies in the options, and values treated
+// as missing produce errors wrapping ErrKeyNotFound when translated.
+//
+// The returned Translator is also an EnumerableTranslator.
+func NewYAMLTranslatorWithOptions(yml []byte, opts YAMLOptions) (Translator, error) {
+	ts := make(map[string]*yamlNode)
 	err := yaml.Unmarshal(yml, ts)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshalling yaml translations: %v", err)
 	}
-	yts := yamlTranslationizeMap(ts)
+	yts := yamlTranslationizeMap(ts, &opts)
 	yt := &yamlTranslator{
-		separator:    separator,
+		separator:    opts.Separator,
 		translations: yts,
 	}
+	if opts.Flatten {
+		yt.flat = make(map[string]string)
+		yt.groups = make(map[string]bool)
+		yts.flatten(yt.flat, yt.groups, "", opts.Separator)
+	}
 	return yt, nil
 }
 
 func (t *yamlTranslator) Translate(key string, properties TranslationProperties) (string, error) {
+	if t.flat != nil {
+		if translation, ok := t.flat[key]; ok {
+			return translation, nil
+		}
+		err := ErrKeyNotFound
+		if t.groups[key] {
+			err = ErrIncompletePath
+		}
+		return "", fmt.Errorf("translating %v: %w", strconv.Quote(key), err)
+	}
 	var path []string
 	if t.separator == "" {
 		path = append(path, key)
@@ -60,57 +174,182 @@ func (t *yamlTranslator) Translate(key string, properties TranslationProperties)
 	}
 	translation, err := t.translations.find(path)
 	if err != nil {
-		return translation, fmt.Errorf("translating %v: %v", strconv.Quote(key), err)
+		return translation, fmt.Errorf("translating %v: %w", strconv.Quote(key), err)
 	}
 	return translation, nil
 }
 
+// Keys returns the sorted list of keys with a translation available, joining
+// the route to each translation with the translator's separator. When the
+// separator is empty only the translations at the top of the tree are
+// reachable, so only their keys are returned.
+func (t *yamlTranslator) Keys() []string {
+	keys := []string{}
+	if t.flat != nil {
+		for k := range t.flat {
+			keys = append(keys, k)
+		}
+	} else if t.separator == "" {
+		for k, v := range t.translations {
+			if _, ok := v.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+	} else {
+		keys = t.translations.appendKeys(keys, "", t.separator)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (yts yamlTranslations) find(path []string) (string, error) {
 	if len(path) == 0 {
-		return "", fmt.Errorf("incomplete path")
+		return "", ErrIncompletePath
 	}
 	k := path[0]
 	path = path[1:]
 	v, ok := yts[k]
 	if !ok {
-		return "", fmt.Errorf("not found")
+		return "", ErrKeyNotFound
 	}
 	switch v := v.(type) {
 	case yamlTranslations:
 		return v.find(path)
 	case string:
 		if len(path) != 0 {
-			return "", fmt.Errorf("not found")
+			return "", ErrKeyNotFound
 		}
 		return v, nil
 	default:
-		panic(fmt.Sprintf("unexpected value of type %T in yamlTranslations", v))
+		return "", fmt.Errorf("unexpected value of type %T", v)
 	}
 }
 
-func yamlTranslationizeMap(ts map[interface{}]interface{}) yamlTranslations {
+func (yts yamlTranslations) appendKeys(keys []string, prefix, separator string) []string {
+	for k, v := range yts {
+		if strings.Contains(k, separator) {
+			continue
+		}
+		switch v := v.(type) {
+		case yamlTranslations:
+			keys = v.appendKeys(keys, prefix+k+separator, separator)
+		case string:
+			keys = append(keys, prefix+k)
+		}
+	}
+	return keys
+}
+
+// flatten adds the translations in the tree to the flat map indexed by their
+// full key, and the full keys of groups of translations to the groups set.
+// Keys unreachable by splitting with the separator are left out, so lookups
+// behave as when walking the tree.
+func (yts yamlTranslations) flatten(flat map[string]string, groups map[string]bool, prefix, separator string) {
+	for k, v := range yts {
+		if separator == "" && prefix != "" || separator != "" && strings.Contains(k, separator) {
+			continue
+		}
+		switch v := v.(type) {
+		case yamlTranslations:
+			groups[prefix+k] = true
+			v.flatten(flat, groups, prefix+k+separator, separator)
+		case string:
+			flat[prefix+k] = v
+		}
+	}
+}
+
+// UnmarshalYAML decodes a YAML value into the node, keeping the original text
+// of scalars along with their decoded value. Mappings and sequences are
+// decoded straight into the node's children or items, so each level of the
+// document is decoded once; decoding a value of another kind into them fails
+// without descending into it.
+func (n *yamlNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	mapErr := unmarshal(&n.children)
+	if mapErr == nil && n.children != nil {
+		return nil
+	}
+	n.children = nil
+	if err := unmarshal(&n.items); err == nil && n.items != nil {
+		return nil
+	}
+	n.items = nil
+	err := unmarshal(&n.value)
+	if err != nil {
+		return err
+	}
+	switch n.value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return mapErr
+	}
+	return unmarshal(&n.text)
+}
+
+func yamlTranslationizeMap(ts map[string]*yamlNode, opts *YAMLOptions) yamlTranslations {
 	yts := make(yamlTranslations)
 	for k, v := range ts {
-		yts[fmt.Sprintf("%v", k)] = yamlTranslationizeValue(v)
+		if tv, ok := yamlTranslationizeValue(v, opts); ok {
+			yts[k] = tv
+		}
 	}
 	return yts
 }
 
-func yamlTranslationizeValue(v interface{}) interface{} {
-	switch v := v.(type) {
-	case map[interface{}]interface{}:
-		return yamlTranslationizeMap(v)
-	case []interface{}:
-		return yamlTranslationizeSlice(v)
+func yamlTranslationizeValue(n *yamlNode, opts *YAMLOptions) (interface{}, bool) {
+	if n == nil {
+		return "", opts.Nulls == YAMLNullAsEmpty
+	}
+	if n.children != nil {
+		return yamlTranslationizeMap(n.children, opts), true
+	}
+	if n.items != nil {
+		return yamlTranslationizeSlice(n.items, opts), true
+	}
+	switch v := n.value.(type) {
+	case nil:
+		return "", opts.Nulls == YAMLNullAsEmpty
+	case bool:
+		return yamlTranslationizeScalar(n, opts.Booleans)
+	case int, int64, uint64, float64:
+		return yamlTranslationizeScalar(n, opts.Numbers)
+	case string:
+		return yamlTranslationizeString(v, opts.BlockScalars), true
+	default:
+		return n.text, true
+	}
+}
+
+func yamlTranslationizeScalar(n *yamlNode, policy YAMLScalarPolicy) (interface{}, bool) {
+	switch policy {
+	case YAMLScalarAsValue:
+		return fmt.Sprintf("%v", n.value), true
+	case YAMLScalarAsMissing:
+		return "", false
+	default:
+		return n.text, true
+	}
+}
+
+func yamlTranslationizeString(s string, policy YAMLBlockScalarPolicy) string {
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	switch policy {
+	case YAMLBlockScalarTrimTrailingNewlines:
+		return strings.TrimRight(s, "\n")
+	case YAMLBlockScalarJoinLines:
+		return strings.Join(strings.Split(strings.TrimRight(s, "\n"), "\n"), " ")
 	default:
-		return fmt.Sprintf("%v", v)
+		return s
 	}
 }
 
-func yamlTranslationizeSlice(ts []interface{}) yamlTranslations {
+func yamlTranslationizeSlice(ts []*yamlNode, opts *YAMLOptions) yamlTranslations {
 	yts := make(yamlTranslations)
 	for k, v := range ts {
-		yts[fmt.Sprintf("%v", k)] = yamlTranslationizeValue(v)
+		if tv, ok := yamlTranslationizeValue(v, opts); ok {
+			yts[strconv.Itoa(k)] = tv
+		}
 	}
 	return yts
 }
@@ -1,7 +1,11 @@
 package katolomb_test
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/pbanos/katolomb"
@@ -102,3 +106,230 @@ numbers:
 		}
 	}
 }
+
+func TestNewYAMLTranslatorWithOptions(t *testing.T) {
+	yml := []byte(`---
+price: 1.50
+count: 0x1F
+enabled: yes
+nothing: ~
+empty:
+literal: |
+  first line
+  second line
+folded: >
+  first line
+  second line
+list:
+- one
+- ~
+- 2.0`)
+	defaults := katolomb.DefaultYAMLOptions()
+	asValues := katolomb.DefaultYAMLOptions()
+	asValues.Nulls = katolomb.YAMLNullAsEmpty
+	asValues.Booleans = katolomb.YAMLScalarAsValue
+	asValues.Numbers = katolomb.YAMLScalarAsValue
+	asValues.BlockScalars = katolomb.YAMLBlockScalarTrimTrailingNewlines
+	asMissing := katolomb.DefaultYAMLOptions()
+	asMissing.Booleans = katolomb.YAMLScalarAsMissing
+	asMissing.Numbers = katolomb.YAMLScalarAsMissing
+	asMissing.BlockScalars = katolomb.YAMLBlockScalarJoinLines
+	testCases := []struct {
+		opts                 katolomb.YAMLOptions
+		key                  string
+		result               string
+		translationErrNotNil bool
+		description          string
+	}{
+		{defaults, "price", "1.50", false, "keeping the original text of a float"},
+		{defaults, "count", "0x1F", false, "keeping the original text of an integer"},
+		{defaults, "enabled", "yes", false, "keeping the original text of a boolean"},
+		{defaults, "nothing", "", true, "treating an explicit null as missing"},
+		{defaults, "empty", "", true, "treating an implicit null as missing"},
+		{defaults, "literal", "first line\nsecond line\n", false, "keeping a literal block scalar"},
+		{defaults, "folded", "first line second line\n", false, "keeping a folded block scalar"},
+		{defaults, "list.1", "", true, "treating a null list item as missing"},
+		{defaults, "list.2", "2.0", false, "keeping the original text of a number in a list"},
+		{asValues, "price", "1.5", false, "formatting the value of a float"},
+		{asValues, "count", "31", false, "formatting the value of an integer"},
+		{asValues, "enabled", "true", false, "formatting the value of a boolean"},
+		{asValues, "nothing", "", false, "treating an explicit null as empty"},
+		{asValues, "empty", "", false, "treating an implicit null as empty"},
+		{asValues, "literal", "first line\nsecond line", false, "trimming the trailing newline of a literal block scalar"},
+		{asValues, "list.1", "", false, "treating a null list item as empty"},
+		{asMissing, "price", "", true, "treating a float as missing"},
+		{asMissing, "enabled", "", true, "treating a boolean as missing"},
+		{asMissing, "literal", "first line second line", false, "joining the lines of a literal block scalar"},
+	}
+	for _, tc := range testCases {
+		translator, err := katolomb.NewYAMLTranslatorWithOptions(yml, tc.opts)
+		if err != nil {
+			t.Fatalf("expected NewYAMLTranslatorWithOptions not to return error, got %v", err)
+		}
+		result, err := translator.Translate(tc.key, katolomb.NewTranslationProperties(nil))
+		errNotNil := err != nil
+		if errNotNil != tc.translationErrNotNil {
+			if errNotNil {
+				t.Errorf("expected Translate not to return error when %v", tc.description)
+			} else {
+				t.Errorf("expected Translate to return error when %v", tc.description)
+			}
+		}
+		if result != tc.result {
+			t.Errorf("expected Translate to return %v when %v, got %v", strconv.Quote(tc.result), tc.description, strconv.Quote(result))
+		}
+	}
+}
+
+func TestNewYAMLTranslatorWithUnexpectedValues(t *testing.T) {
+	documents := []string{
+		"- a\n- b",
+		"key: !!binary aGVsbG8=",
+		"key: 2001-12-14t21:59:43.10-05:00",
+		"? [a, b]\n: c",
+		"key: {a: [1, {b: ~}], c: [[], {}]}",
+		"key: &anchor {a: b}\nother: *anchor",
+		"~: null",
+		"1.50: x\ntrue: y",
+	}
+	keys := []string{"key", "key.a", "key.a.1.b", "key.c.0", "other.a", "", "1.50", "true", "0"}
+	for _, doc := range documents {
+		translator, err := katolomb.NewYAMLTranslator([]byte(doc))
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			translator.Translate(key, katolomb.NewTranslationProperties(nil))
+		}
+	}
+}
+
+func TestNewYAMLTranslatorWithFlattenOption(t *testing.T) {
+	yml := []byte(`---
+greetings:
+  hello: Hello!
+  bye:
+    night: Good night!
+numbers:
+- zero
+- one
+"dotted.key": unreachable
+top: Top`)
+	keys := []string{"greetings.hello", "greetings.bye.night", "greetings.bye", "greetings", "greetings.hello.world", "numbers.1", "numbers.2", "dotted.key", "top", "top.more", "", "missing"}
+	for _, separator := range []string{".", "/", ""} {
+		treeOpts := katolomb.DefaultYAMLOptions()
+		treeOpts.Separator = separator
+		flatOpts := treeOpts
+		flatOpts.Flatten = true
+		tree, err := katolomb.NewYAMLTranslatorWithOptions(yml, treeOpts)
+		if err != nil {
+			t.Fatalf("expected NewYAMLTranslatorWithOptions not to return error, got %v", err)
+		}
+		flat, err := katolomb.NewYAMLTranslatorWithOptions(yml, flatOpts)
+		if err != nil {
+			t.Fatalf("expected NewYAMLTranslatorWithOptions not to return error, got %v", err)
+		}
+		for _, key := range keys {
+			key = strings.Replace(key, ".", separator, -1)
+			treeResult, treeErr := tree.Translate(key, nil)
+			flatResult, flatErr := flat.Translate(key, nil)
+			if treeResult != flatResult {
+				t.Errorf("expected flattened Translate to return %v for %v with separator %v, got %v", strconv.Quote(treeResult), strconv.Quote(key), strconv.Quote(separator), strconv.Quote(flatResult))
+			}
+			for _, target := range []error{katolomb.ErrKeyNotFound, katolomb.ErrIncompletePath} {
+				if errors.Is(treeErr, target) != errors.Is(flatErr, target) {
+					t.Errorf("expected flattened Translate to return error %v for %v with separator %v, got %v", treeErr, strconv.Quote(key), strconv.Quote(separator), flatErr)
+				}
+			}
+		}
+		treeKeys := tree.(katolomb.EnumerableTranslator).Keys()
+		flatKeys := flat.(katolomb.EnumerableTranslator).Keys()
+		if !reflect.DeepEqual(treeKeys, flatKeys) {
+			t.Errorf("expected flattened Keys to return %v with separator %v, got %v", treeKeys, strconv.Quote(separator), flatKeys)
+		}
+	}
+	opts := katolomb.DefaultYAMLOptions()
+	opts.Flatten = true
+	translator, err := katolomb.NewYAMLTranslatorWithOptions(yml, opts)
+	if err != nil {
+		t.Fatalf("expected NewYAMLTranslatorWithOptions not to return error, got %v", err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		translator.Translate("greetings.bye.night", nil)
+	})
+	if allocs != 0 {
+		t.Errorf("expected flattened Translate not to allocate for hits, got %v allocations", allocs)
+	}
+}
+
+func deepYAML(depth int) ([]byte, string) {
+	var b strings.Builder
+	key := make([]string, 0, depth)
+	for d := 0; d < depth; d++ {
+		fmt.Fprintf(&b, "%sl%d:\n", strings.Repeat("  ", d), d)
+		key = append(key, fmt.Sprintf("l%d", d))
+	}
+	fmt.Fprintf(&b, "%sleaf: Deep translation\n", strings.Repeat("  ", depth))
+	key = append(key, "leaf")
+	return []byte(b.String()), strings.Join(key, ".")
+}
+
+func largeYAML(keys int) ([]byte, []string) {
+	var b strings.Builder
+	lookups := make([]string, 0, keys)
+	for s := 0; s < keys/100; s++ {
+		fmt.Fprintf(&b, "section%d:\n", s)
+		for k := 0; k < 100; k++ {
+			fmt.Fprintf(&b, "  key%d: Translation %d.%d\n", k, s, k)
+			lookups = append(lookups, fmt.Sprintf("section%d.key%d", s, k))
+		}
+	}
+	return []byte(b.String()), lookups
+}
+
+func benchmarkYAMLTranslator(b *testing.B, yml []byte, keys []string, flatten bool) {
+	opts := katolomb.DefaultYAMLOptions()
+	opts.Flatten = flatten
+	translator, err := katolomb.NewYAMLTranslatorWithOptions(yml, opts)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := translator.Translate(keys[n%len(keys)], nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkYAMLTranslatorDeepTree(b *testing.B) {
+	yml, key := deepYAML(20)
+	benchmarkYAMLTranslator(b, yml, []string{key}, false)
+}
+
+func BenchmarkYAMLTranslatorDeepFlattened(b *testing.B) {
+	yml, key := deepYAML(20)
+	benchmarkYAMLTranslator(b, yml, []string{key}, true)
+}
+
+func BenchmarkYAMLTranslatorLargeTree(b *testing.B) {
+	yml, keys := largeYAML(100000)
+	benchmarkYAMLTranslator(b, yml, keys, false)
+}
+
+func BenchmarkYAMLTranslatorLargeFlattened(b *testing.B) {
+	yml, keys := largeYAML(100000)
+	benchmarkYAMLTranslator(b, yml, keys, true)
+}
+
+func BenchmarkNewYAMLTranslatorDeepTree(b *testing.B) {
+	yml, _ := deepYAML(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := katolomb.NewYAMLTranslator(yml); err != nil {
+			b.Fatal(err)
+		}
+	}
+}